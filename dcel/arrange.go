@@ -0,0 +1,167 @@
+package dcel
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+)
+
+// arrange builds the subdivision formed by segs, given the points
+// intersect.Sweep returned for them. Each segment is split at every
+// point which touches it, and pieces of segments which overlap are
+// merged. Along with the dcel, arrange returns for each edge,
+// HalfEdges[2i] and its twin, the indices of the segments it came
+// from. HalfEdges[2i] runs from whichever of its points
+// intersect.Before finds first to the other.
+func arrange(segs []geom.FullEdge, pts []intersect.Intersection) (*DCEL, [][]int) {
+	dc := New()
+	dc.Vertices = make([]*Vertex, len(pts))
+	for i, sp := range pts {
+		dc.Vertices[i] = NewVertex(sp.X(), sp.Y(), 0)
+	}
+
+	// As pts are in sweep order, consecutive points
+	// touching a segment are consecutive along it.
+	last := make([]int, len(segs))
+	for i := range last {
+		last[i] = -1
+	}
+	edgeIndex := make(map[[2]int]int)
+	srcs := [][]int{}
+	for i, sp := range pts {
+		for _, s := range sp.Segments {
+			j := last[s]
+			last[s] = i
+			if j == -1 {
+				continue
+			}
+			k, ok := edgeIndex[[2]int{j, i}]
+			if !ok {
+				k = len(srcs)
+				edgeIndex[[2]int{j, i}] = k
+				srcs = append(srcs, nil)
+				e := NewEdge()
+				e.Origin = dc.Vertices[j]
+				t := NewEdge()
+				t.Origin = dc.Vertices[i]
+				e.SetTwin(t)
+				dc.HalfEdges = append(dc.HalfEdges, e, t)
+			}
+			srcs[k] = append(srcs[k], s)
+		}
+	}
+
+	// Each vertex's edges are sorted counter-clockwise. A half
+	// edge's face lies to its right, so it is followed by the
+	// edge after its twin around the vertex it leads to.
+	outs := make(map[*Vertex][]*Edge)
+	for _, e := range dc.HalfEdges {
+		outs[e.Origin] = append(outs[e.Origin], e)
+	}
+	for v, es := range outs {
+		angles := make(map[*Edge]float64, len(es))
+		for _, e := range es {
+			angles[e] = math.Atan2(e.Twin.Origin.Y()-v.Y(), e.Twin.Origin.X()-v.X())
+		}
+		sort.Slice(es, func(i, j int) bool {
+			return angles[es[i]] < angles[es[j]]
+		})
+		v.OutEdge = es[0]
+		for i, e := range es {
+			e.Twin.SetNext(es[(i+1)%len(es)])
+		}
+	}
+
+	buildFaces(dc)
+	return dc, srcs
+}
+
+// buildFaces creates the faces of dc from the cycles of its
+// half edges, whose Next and Twin pointers must already be set,
+// with each face to the right of its edges. Clockwise cycles
+// bound new faces. Every other cycle is the outside of some
+// component of dc, and becomes a hole in the innermost face
+// around it, or in the outer face.
+func buildFaces(dc *DCEL) {
+	type cycle struct {
+		edges []*Edge
+		area  float64
+	}
+	seen := make(map[*Edge]bool)
+	bounded := []cycle{}
+	holes := []cycle{}
+	for _, e := range dc.HalfEdges {
+		if seen[e] {
+			continue
+		}
+		c := cycle{e.EdgeChain(), 0}
+		sp := geom.NewSpan()
+		o := e.Origin
+		for _, e2 := range c.edges {
+			seen[e2] = true
+			sp = sp.Expand(e2.Origin)
+			a, b := e2.Origin, e2.Next.Origin
+			c.area += (a.X()-o.X())*(b.Y()-o.Y()) - (b.X()-o.X())*(a.Y()-o.Y())
+		}
+		c.area /= 2
+		d := sp.Diff()
+		if c.area < -1e-9*(d.X()*d.X()+d.Y()*d.Y()) {
+			bounded = append(bounded, c)
+		} else {
+			holes = append(holes, c)
+		}
+	}
+	for _, c := range bounded {
+		f := NewFace()
+		f.Outer = c.edges[0]
+		for _, e := range c.edges {
+			e.Face = f
+		}
+		dc.Faces = append(dc.Faces, f)
+	}
+
+	// Components are found by joining each edge's endpoints.
+	parent := make(map[*Vertex]*Vertex)
+	var find func(*Vertex) *Vertex
+	find = func(v *Vertex) *Vertex {
+		p, ok := parent[v]
+		if !ok || p == v {
+			return v
+		}
+		parent[v] = find(p)
+		return parent[v]
+	}
+	for _, e := range dc.HalfEdges {
+		parent[find(e.Origin)] = find(e.Twin.Origin)
+	}
+
+	// Holes are found before they are added,
+	// so that Contains only sees outer boundaries.
+	around := make([]*Face, len(holes))
+	for i, h := range holes {
+		c := find(h.edges[0].Origin)
+		best := math.Inf(1)
+		for j, b := range bounded {
+			if find(b.edges[0].Origin) == c || -b.area >= best {
+				continue
+			}
+			f := dc.Faces[OUTER_FACE+1+j]
+			if f.Contains(h.edges[0].Origin) {
+				around[i] = f
+				best = -b.area
+			}
+		}
+	}
+	for i, h := range holes {
+		f := around[i]
+		if f == nil {
+			f = dc.Faces[OUTER_FACE]
+		}
+		f.Inner = append(f.Inner, h.edges[0])
+		for _, e := range h.edges {
+			e.Face = f
+		}
+	}
+}
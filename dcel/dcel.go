@@ -43,7 +43,9 @@ func (dc *DCEL) String() string {
 	for i, f := range dc.Faces {
 		s += "f" + strconv.Itoa(i)
 		s += " Inner: "
-		s += f.Inner.String()
+		for _, e := range f.Inner {
+			s += e.String()
+		}
 		s += " Outer: "
 		s += f.Outer.String()
 		s += "\n"
@@ -188,9 +190,11 @@ func (dc *DCEL) CorrectDirectionality(f *Face) {
 		f.Outer.Flip()
 	}
 	//fmt.Println(err, clock)
-	clock, err = f.Inner.IsClockwise()
-	if err == nil && !clock {
-		f.Inner.Flip()
+	for _, e := range f.Inner {
+		clock, err = e.IsClockwise()
+		if err == nil && !clock {
+			e.Flip()
+		}
 	}
 }
 
@@ -201,14 +205,28 @@ func (dc *DCEL) CorrectDirectionalityAll() {
 		clock, err := f.Outer.IsClockwise()
 		if err == nil && clock {
 			f.Outer = f.Outer.Twin
+			f.Outer.setChainFace(f)
 		}
-		clock, err = f.Inner.IsClockwise()
-		if err == nil && !clock {
-			f.Inner = f.Inner.Twin
+		for i, e := range f.Inner {
+			clock, err = e.IsClockwise()
+			if err == nil && !clock {
+				f.Inner[i] = e.Twin
+				f.Inner[i].setChainFace(f)
+			}
 		}
 	}
 }
 
+// setChainFace labels every edge in e's chain as
+// bordering f. When a face takes over its twin chain,
+// that chain's edges still point to the face on the
+// other side until they are relabeled.
+func (e *Edge) setChainFace(f *Face) {
+	for _, e2 := range e.EdgeChain() {
+		e2.Face = f
+	}
+}
+
 // CorrectTwins modifies the ordering on twins
 // inside the DCEL such that dc.HalfEdges[i] is
 // the twin of dc.HalfEdges[i+1] for all even
@@ -245,9 +263,10 @@ func (dc *DCEL) Copy() *DCEL {
 		fPointerMap[f] = i
 		f2 := NewFace()
 		dc2.Faces[i] = f2
-		if f.Inner != nil {
-			f2.Inner = dc2.HalfEdges[ePointerMap[f.Inner]]
-			f2.Inner.Face = f2
+		for _, e := range f.Inner {
+			e2 := dc2.HalfEdges[ePointerMap[e]]
+			e2.Face = f2
+			f2.Inner = append(f2.Inner, e2)
 		}
 		if f.Outer != nil {
 			f2.Outer = dc2.HalfEdges[ePointerMap[f.Outer]]
@@ -276,6 +295,9 @@ func (dc *DCEL) Copy() *DCEL {
 		if e.Origin != nil {
 			e2.Origin = dc2.Vertices[vPointerMap[e.Origin]]
 		}
+		if e.Face != nil {
+			e2.Face = dc2.Faces[fPointerMap[e.Face]]
+		}
 	}
 
 	return dc2
@@ -315,8 +337,9 @@ func (dc *DCEL) VerticesSorted(ds ...int) []int {
 // The job of creating new faces is delayed because if a series of
 // ConnectVerts is called on the same face, calling code won't be
 // able to easily tell which face the sequential diagonals land in.
-//
-// This function doesn't cover enough cases to work.
+// Because of that, a vertex can have more than one edge on f
+// when ConnectVerts is called, and the edge used is the one whose
+// wedge around the vertex contains the new diagonal.
 func (dc *DCEL) ConnectVerts(a, b *Vertex, f *Face) {
 	// If a and b's outEdges and twins do not
 	// share a face, this connection would
@@ -324,56 +347,21 @@ func (dc *DCEL) ConnectVerts(a, b *Vertex, f *Face) {
 	// it may be allowed in the future, recursively
 	// adding vertices and edges until the connection
 	// is complete.
-	edgesA := a.AllEdges()
-	edgesB := b.AllEdges()
-	// It should be impossible for the same face
-	// to exist on two edges off of the one vertex
-	// in a well-formed DCEL, and if that happens we
-	// may change the wrong edges.
-	var e1, e2 *Edge
-	for _, e := range edgesA {
-		if e.Face == f {
-			e1 = e
-			break
-		}
-	}
-	for _, e := range edgesB {
-		if e.Face == f {
-			e2 = e
-			break
-		}
-	}
-	// If the two vertices share more than one face,
-	// there's already an edge here and we can't add
-	// another one, unless one of those faces encloses
-	// the other, in which case we use the enclosed face.
-	//
-	// ^^ this is not correct. Consider two vertices on
-	// a pseudo-vertical line with both the left and right faces
-	// defined. What we need is a good algorithm to determine
-	// which of the two faces contains the diagonal
-	//
-	// The way this algorithm solves this is by taking the
-	// face being split in as a hint.
+	e1 := a.wedgeToward(b, f)
+	e2 := b.wedgeToward(a, f)
 
-	//   e1   e1.prev
+	//      e1.prev  e1
 	//  ----A-----
 	//      |
 	//  new2|new1
 	//      |
 	//  ----B-----
-	// e2.prev  e2
-	//
-	// Problem: what if e1.prev is not on the right?
-	// what about
+	// e2    e2.prev
 	//
-	// e1.prev  e1
-	//  ----A-----
-	//      |
-	//  new2|new1
-	//      |
-	//  ----B-----
-	// e2.prev  e2
+	// e1 and e2 are the edges leaving A and B whose
+	// wedges (between e.Prev.Twin and e) contain the
+	// diagonal, so the new edges are spliced in between
+	// each edge and its predecessor.
 
 	new1 := NewEdge()
 	new1.Origin = a
@@ -394,3 +382,61 @@ func (dc *DCEL) ConnectVerts(a, b *Vertex, f *Face) {
 
 	dc.HalfEdges = append(dc.HalfEdges, new1, new2)
 }
+
+// wedgeToward returns the edge leaving v on face f whose wedge,
+// the angle between the edge and the previous edge on its chain,
+// contains the direction from v to v2. If only one edge of v lies
+// on f, that edge is returned.
+func (v *Vertex) wedgeToward(v2 *Vertex, f *Face) *Edge {
+	edges := v.AllEdges()
+	candidates := make([]*Edge, 0, 1)
+	for _, e := range edges {
+		if e.Face == f {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) < 2 {
+		if len(candidates) == 0 {
+			return nil
+		}
+		return candidates[0]
+	}
+	angle := func(p geom.D2) float64 {
+		return math.Atan2(p.Y()-v.Y(), p.X()-v.X())
+	}
+	// between reports whether t is strictly within the
+	// counter-clockwise sweep from angle a1 to a2.
+	between := func(a1, a2, t float64) bool {
+		span := math.Mod(a2-a1+4*math.Pi, 2*math.Pi)
+		off := math.Mod(t-a1+4*math.Pi, 2*math.Pi)
+		return off > 0 && off < span
+	}
+	target := angle(v2)
+	for _, e := range candidates {
+		in := e.Prev.Twin
+		if in == e {
+			return e
+		}
+		aOut := angle(e.Twin.Origin)
+		aIn := angle(in.Twin.Origin)
+		// The wedge is whichever side of the two edges has no
+		// other edges of v in it.
+		ccw := true
+		for _, e3 := range edges {
+			if e3 == e || e3 == in {
+				continue
+			}
+			if between(aOut, aIn, angle(e3.Twin.Origin)) {
+				ccw = false
+				break
+			}
+		}
+		if ccw && between(aOut, aIn, target) {
+			return e
+		}
+		if !ccw && between(aIn, aOut, target) {
+			return e
+		}
+	}
+	return candidates[0]
+}
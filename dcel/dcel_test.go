@@ -0,0 +1,141 @@
+package dcel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// faceIndex returns the index of f in dc.Faces, or -1.
+func faceIndex(dc *dcel.DCEL, f *dcel.Face) int {
+	for i, f2 := range dc.Faces {
+		if f2 == f {
+			return i
+		}
+	}
+	return -1
+}
+
+// chainArea returns the signed area enclosed by e's chain.
+func chainArea(e *dcel.Edge) float64 {
+	area := 0.0
+	for _, e2 := range e.EdgeChain() {
+		a, b := e2.Origin, e2.Next.Origin
+		area += a.X()*b.Y() - b.X()*a.Y()
+	}
+	return area / 2
+}
+
+func TestCopyFaces(t *testing.T) {
+	// Every edge of a copy should border the copy
+	// of the face its original borders, not only the
+	// edges its faces point to.
+	dc := dcel.Random2DDCELWithSeed(1000, 10, 1)
+	dc2 := dc.Copy()
+	for i, e := range dc.HalfEdges {
+		assert.Equal(t, faceIndex(dc, e.Face), faceIndex(dc2, dc2.HalfEdges[i].Face), "edge %d", i)
+	}
+	assert.Empty(t, dc2.Validate())
+}
+
+func TestCorrectDirectionalityAll(t *testing.T) {
+	// Reverse the winding of a square, keeping it consistent,
+	// so that its face's outer chain runs clockwise.
+	dc := dcel.Rect(0, 0, 10, 10)
+	f, outer := dc.Faces[1], dc.Faces[0]
+	for _, e := range dc.HalfEdges {
+		if e.Face == f {
+			e.Face = outer
+		} else {
+			e.Face = f
+		}
+	}
+	f.Outer, outer.Inner[0] = outer.Inner[0], f.Outer
+	clock, err := f.Outer.IsClockwise()
+	assert.Nil(t, err)
+	assert.True(t, clock)
+
+	// Taking over the twin chain should relabel its edges,
+	// which still border the other face.
+	dc.CorrectDirectionalityAll()
+	for _, e := range f.Outer.EdgeChain() {
+		assert.Equal(t, f, e.Face)
+	}
+	for _, e := range outer.Inner[0].EdgeChain() {
+		assert.Equal(t, outer, e.Face)
+	}
+	assert.Empty(t, dc.Validate())
+}
+
+func TestIsClockwiseNearColinear(t *testing.T) {
+	// The vertex of this quad with the least y value, its
+	// second, is nearly colinear with its neighbors, so far
+	// from the origin the turn it makes is lost to rounding.
+	quad := func(x, y float64) []geom.D2 {
+		return []geom.D2{
+			geom.NewPoint(x-1, y-1, 0),
+			geom.NewPoint(x, y-1-1e-8, 0),
+			geom.NewPoint(x+1, y-1, 0),
+			geom.NewPoint(x, y+10, 0),
+		}
+	}
+	want, err := dcel.Polygon(quad(0, 0)).Faces[0].Inner[0].IsClockwise()
+	assert.Nil(t, err)
+	got, err := dcel.Polygon(quad(1e6, 1e6)).Faces[0].Inner[0].IsClockwise()
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestConnectVertsWedge(t *testing.T) {
+	// As ConnectVerts does not split faces, a second diagonal
+	// from the same vertex finds two of its edges on the face,
+	// and has to be spliced into the wedge it lies in.
+	hex := []geom.D2{
+		geom.NewPoint(0, 10, 0),
+		geom.NewPoint(-10, 5, 0),
+		geom.NewPoint(-10, -5, 0),
+		geom.NewPoint(0, -10, 0),
+		geom.NewPoint(10, -5, 0),
+		geom.NewPoint(10, 5, 0),
+	}
+	for _, order := range [][2]int{{2, 4}, {4, 2}} {
+		dc := dcel.Polygon(hex)
+		f := dc.Faces[1]
+		for _, i := range order {
+			dc.ConnectVerts(dc.Vertices[0], dc.Vertices[i], f)
+		}
+		// The diagonals split the hexagon into two
+		// triangles and a quad, each of which should
+		// be its own chain.
+		seen := map[*dcel.Edge]bool{}
+		sizes := map[int]int{}
+		area := 0.0
+		for _, e := range dc.HalfEdges {
+			if e.Face != f || seen[e] {
+				continue
+			}
+			chain := e.EdgeChain()
+			for _, e2 := range chain {
+				seen[e2] = true
+			}
+			sizes[len(chain)]++
+			area += math.Abs(chainArea(e))
+		}
+		assert.Equal(t, map[int]int{3: 2, 4: 1}, sizes, "order %v", order)
+		assert.InDelta(t, 300.0, area, 1e-9, "order %v", order)
+	}
+}
+
+func TestRandom2DDCELArea(t *testing.T) {
+	// Splitting two edges along the same line
+	// would leave a face with no area.
+	for i := 0; i < 100; i++ {
+		dc := dcel.Random2DDCELWithSeed(1000, 25, int64(i))
+		for j, f := range dc.Faces[1:] {
+			assert.True(t, math.Abs(chainArea(f.Outer)) > 1e-6, "seed %d face %d", i, j+1)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package delaunay
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// Constrained returns the constrained Delaunay triangulation of
+// inDc, which keeps every edge of inDc, and is otherwise as close
+// to Delaunay as those edges allow. Edges which pass through
+// vertices of inDc are split there. Each bounded face of the
+// returned dcel is a triangle inside a bounded face of inDc, and
+// the rest of the plane is its outer face. Along with it,
+// Constrained returns a map from each face of the triangulation
+// to the face of inDc which contains it. The vertices of the
+// triangulation are those of inDc, in the same order. If inDc is
+// not planar, by SelfIntersections, Constrained returns an error.
+func Constrained(inDc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	return ConstrainedWithRand(inDc, nil)
+}
+
+// ConstrainedWithRand acts as Constrained, inserting vertices in
+// an order shuffled by rnd. If rnd is nil, the global source of
+// math/rand is used.
+func ConstrainedWithRand(inDc *dcel.DCEL, rnd *rand.Rand) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	found, err := inDc.SelfIntersections()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(found) != 0 {
+		return nil, nil, errors.New("Edges of the DCEL to triangulate cross at " +
+			found[0].Point.String())
+	}
+	pts := make([]geom.D2, len(inDc.Vertices))
+	vertices := make(map[*dcel.Vertex]int, len(inDc.Vertices))
+	for i, v := range inDc.Vertices {
+		pts[i] = v.Point
+		vertices[v] = i
+	}
+	tr, index, err := build(pts, rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr.incidence()
+
+	var outer *dcel.Face
+	if len(inDc.Faces) > dcel.OUTER_FACE {
+		outer = inDc.Faces[dcel.OUTER_FACE]
+	}
+	// right holds the face of inDc to the right of each
+	// directed edge which lies along an edge of inDc.
+	right := make(map[[2]int]*dcel.Face)
+	fixed := make(map[[2]int]bool)
+	for i := 0; i+1 < len(inDc.HalfEdges); i += 2 {
+		e := inDc.HalfEdges[i]
+		a, b := index[vertices[e.Origin]], index[vertices[e.Twin.Origin]]
+		path := tr.constrain(a, b, fixed)
+		for j := 1; j < len(path); j++ {
+			x, y := path[j-1], path[j]
+			right[[2]int{x, y}] = e.Face
+			right[[2]int{y, x}] = e.Twin.Face
+		}
+	}
+
+	// Each triangle lies in the face of inDc to the right
+	// of any edge of inDc it has, or in the same face as
+	// its neighbors across other edges.
+	srcs := make(map[*tri]*dcel.Face)
+	queue := []*tri{}
+	for _, t := range tr.tris {
+		if t.ghost() {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			a, b := t.v[(i+1)%3], t.v[(i+2)%3]
+			if f, ok := right[[2]int{b, a}]; ok && f != nil {
+				srcs[t] = f
+				queue = append(queue, t)
+				break
+			}
+		}
+	}
+	for len(queue) != 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for i, u := range t.n {
+			a, b := t.v[(i+1)%3], t.v[(i+2)%3]
+			if u.ghost() || fixed[key(a, b)] || srcs[u] != nil {
+				continue
+			}
+			srcs[u] = srcs[t]
+			queue = append(queue, u)
+		}
+	}
+
+	dc, faces := tr.dcel(func(t *tri) bool {
+		return srcs[t] != nil && srcs[t] != outer
+	}, fixed)
+	for i, v := range inDc.Vertices {
+		dc.Vertices[index[i]].Point[2] = v.Z()
+	}
+	faceMap := make(map[*dcel.Face]*dcel.Face, len(dc.Faces))
+	faceMap[dc.Faces[dcel.OUTER_FACE]] = outer
+	for t, f := range faces {
+		faceMap[f] = srcs[t]
+	}
+	return dc, faceMap, nil
+}
+
+// incidence records a triangle around each vertex.
+func (tr *triangulation) incidence() {
+	tr.at = make([]*tri, len(tr.pts))
+	for _, t := range tr.tris {
+		for _, v := range t.v {
+			if v != infinite {
+				tr.at[v] = t
+			}
+		}
+	}
+}
+
+// around returns the triangles which have v as a vertex.
+func (tr *triangulation) around(v int) []*tri {
+	start := tr.at[v]
+	tris := []*tri{}
+	for t := start; ; {
+		tris = append(tris, t)
+		i := t.index(v)
+		t = t.n[(i+2)%3]
+		if t == start {
+			return tris
+		}
+	}
+}
+
+// find returns the triangle with the edge from a to b, and the
+// index of the vertex opposite that edge, or nil if there is none.
+func (tr *triangulation) find(a, b int) (*tri, int) {
+	for _, t := range tr.around(a) {
+		i := t.index(a)
+		if t.v[(i+1)%3] == b {
+			return t, (i + 2) % 3
+		}
+	}
+	return nil, -1
+}
+
+// constrain makes sure the triangulation has edges along the
+// segment from a to b, and adds them to fixed. It returns the
+// vertices along that segment, from a to b.
+func (tr *triangulation) constrain(a, b int, fixed map[[2]int]bool) []int {
+	path := []int{a}
+	for a != b {
+		c := tr.constrainTo(a, b)
+		fixed[key(a, c)] = true
+		tr.restore(fixed)
+		path = append(path, c)
+		a = c
+	}
+	return path
+}
+
+// constrainTo flips edges which cross the segment from a toward
+// b until there is an edge from a along that segment, ending at
+// b or the first vertex in the way. It returns where that edge
+// ends. The edges made along the way are kept to be restored.
+func (tr *triangulation) constrainTo(a, b int) int {
+	tr.made = tr.made[:0]
+	pb := tr.pts[b]
+	// Find the triangle around a which the segment leaves
+	// through, or a vertex on the segment next to a.
+	var x, y int
+	found := false
+	for _, t := range tr.around(a) {
+		if t.ghost() {
+			continue
+		}
+		i := t.index(a)
+		x, y = t.v[(i+1)%3], t.v[(i+2)%3]
+		for _, v := range []int{x, y} {
+			if v == b || (tr.orient(a, b, v) == 0 && geom.Dot2D(diff(tr.pts[v], tr.pts[a]), diff(pb, tr.pts[a])) > 0) {
+				return v
+			}
+		}
+		if tr.orient(a, b, x) < 0 && tr.orient(a, b, y) > 0 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		// The segment leaves the triangulation,
+		// which only happens through rounding.
+		return b
+	}
+
+	// Walk along the segment, keeping the edges it crosses,
+	// with the vertex to its right first.
+	c := b
+	crossed := [][2]int{{x, y}}
+	for {
+		u, i := tr.find(y, x)
+		q := u.v[i]
+		if q == b {
+			break
+		}
+		o := tr.orient(a, b, q)
+		if o == 0 {
+			c = q
+			break
+		}
+		if o < 0 {
+			x = q
+		} else {
+			y = q
+		}
+		crossed = append(crossed, [2]int{x, y})
+	}
+
+	// Edges are flipped in turn, skipping those whose
+	// triangles do not form a convex quadrilateral, until
+	// none cross the segment.
+	for len(crossed) != 0 {
+		e := crossed[0]
+		crossed = crossed[1:]
+		t, i := tr.find(e[0], e[1])
+		t.rotate(i)
+		u := t.n[0]
+		u.rotate(u.edge(t.v[2], t.v[1]))
+		p, q := t.v[0], u.v[0]
+		if tr.orient(p, t.v[1], q) <= 0 || tr.orient(p, q, t.v[2]) <= 0 {
+			crossed = append(crossed, e)
+			continue
+		}
+		tr.flip(t, u)
+		if tr.crosses(a, c, p, q) {
+			crossed = append(crossed, [2]int{p, q})
+		} else {
+			tr.made = append(tr.made, [2]int{p, q})
+		}
+	}
+	return c
+}
+
+// restore flips the edges made by constrainTo, other than
+// those in fixed, until each is locally Delaunay.
+func (tr *triangulation) restore(fixed map[[2]int]bool) {
+	for changed := true; changed; {
+		changed = false
+		for i, e := range tr.made {
+			if fixed[key(e[0], e[1])] {
+				continue
+			}
+			t, j := tr.find(e[0], e[1])
+			t.rotate(j)
+			u := t.n[0]
+			u.rotate(u.edge(t.v[2], t.v[1]))
+			if t.ghost() || u.ghost() || tr.inCircle(u.v[0], u.v[1], u.v[2], t.v[0]) <= 0 {
+				continue
+			}
+			p, q := t.v[0], u.v[0]
+			tr.flip(t, u)
+			tr.made[i] = [2]int{p, q}
+			changed = true
+		}
+	}
+}
+
+// crosses reports whether the segments ab and pq cross
+// at a point inside both of them.
+func (tr *triangulation) crosses(a, b, p, q int) bool {
+	return tr.orient(a, b, p)*tr.orient(a, b, q) < 0 &&
+		tr.orient(p, q, a)*tr.orient(p, q, b) < 0
+}
+
+func diff(a, b geom.D2) geom.Point {
+	return geom.NewPoint(a.X()-b.X(), a.Y()-b.Y(), 0)
+}
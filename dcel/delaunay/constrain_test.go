@@ -0,0 +1,146 @@
+package delaunay_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/delaunay"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// testConstrained checks that tri triangulates the bounded faces
+// of dc, keeping its edges, and that each face of tri is mapped to
+// the face of dc containing it.
+func testConstrained(t *testing.T, dc *dcel.DCEL, tri *dcel.DCEL, faceMap map[*dcel.Face]*dcel.Face) {
+	assert.Empty(t, tri.Validate())
+	assert.Equal(t, dc.Faces[dcel.OUTER_FACE], faceMap[tri.Faces[dcel.OUTER_FACE]])
+	type pair [2]geom.Point
+	edges := make(map[pair]bool)
+	for _, e := range tri.HalfEdges {
+		edges[pair{e.Origin.Point, e.Twin.Origin.Point}] = true
+	}
+	for _, e := range dc.HalfEdges {
+		assert.True(t, edges[pair{e.Origin.Point, e.Twin.Origin.Point}], "missing edge %v", e)
+	}
+	area := 0.0
+	for _, f := range tri.Faces[dcel.OUTER_FACE+1:] {
+		vs := f.Vertices()
+		if !assert.Equal(t, 3, len(vs)) {
+			continue
+		}
+		area += geom.Cross2D(vs[0], vs[2], vs[1]) / 2
+		c := geom.NewPoint((vs[0].X()+vs[1].X()+vs[2].X())/3,
+			(vs[0].Y()+vs[1].Y()+vs[2].Y())/3, 0)
+		src := faceMap[f]
+		if assert.NotNil(t, src) {
+			assert.True(t, src.Contains(c), "%v not in its face", c)
+		}
+	}
+	// The triangles cover the bounded faces of dc.
+	want := 0.0
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		for _, start := range f.Boundaries() {
+			for _, e := range start.EdgeChain() {
+				a, b := e.Origin, e.Next.Origin
+				want -= (a.X()*b.Y() - b.X()*a.Y()) / 2
+			}
+		}
+	}
+	assert.InDelta(t, want, area, 1e-6*math.Abs(want))
+
+	// Edges which are not constraints are locally Delaunay.
+	for _, e := range tri.HalfEdges {
+		f, f2 := e.Face, e.Twin.Face
+		if f == tri.Faces[dcel.OUTER_FACE] || f2 == tri.Faces[dcel.OUTER_FACE] {
+			continue
+		}
+		a, b := e.Origin, e.Twin.Origin
+		c, d := e.Next.Next.Origin, e.Twin.Next.Next.Origin
+		if isEdge(dc, a.Point, b.Point) {
+			continue
+		}
+		assert.False(t, inCircle(a, c, b, d) > 1e-6, "%v to %v is not Delaunay", a, b)
+	}
+}
+
+func isEdge(dc *dcel.DCEL, a, b geom.Point) bool {
+	for _, e := range dc.HalfEdges {
+		if e.Origin.Point == a && e.Twin.Origin.Point == b {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConstrainedRandom(t *testing.T) {
+	for i := int64(0); i < 10; i++ {
+		dc := dcel.Random2DDCELWithSeed(1000, 20, i)
+		tri, faceMap, err := delaunay.ConstrainedWithRand(dc, rand.New(rand.NewSource(i)))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		testConstrained(t, dc, tri, faceMap)
+	}
+}
+
+func TestConstrainedConcave(t *testing.T) {
+	// A C shape, whose notch is in the outer face, and
+	// which has a vertex along its back.
+	dc, err := dcel.FromSegments([]geom.FullEdge{
+		segment(0, 0, 10, 0),
+		segment(10, 0, 10, 2),
+		segment(10, 2, 2, 2),
+		segment(2, 2, 2, 8),
+		segment(2, 8, 10, 8),
+		segment(10, 8, 10, 10),
+		segment(10, 10, 0, 10),
+		segment(0, 10, 0, 5),
+		segment(0, 5, 0, 0),
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	tri, faceMap, err := delaunay.Constrained(dc)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	testConstrained(t, dc, tri, faceMap)
+	assert.Equal(t, 7, len(tri.Faces)-1)
+}
+
+func TestConstrainedHoles(t *testing.T) {
+	dc, _, err := dcel.Overlay(dcel.Rect(0, 0, 100, 100), dcel.Rect(40, 40, 20, 20))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	tri, faceMap, err := delaunay.Constrained(dc)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	testConstrained(t, dc, tri, faceMap)
+	inner := 0
+	for _, f := range tri.Faces[dcel.OUTER_FACE+1:] {
+		if faceMap[f].Contains(geom.NewPoint(50, 50, 0)) {
+			inner++
+		}
+	}
+	assert.Equal(t, 2, inner)
+}
+
+func TestConstrainedNonPlanar(t *testing.T) {
+	dc := dcel.FourPoint(
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(10, 10, 0),
+		geom.NewPoint(10, 0, 0),
+		geom.NewPoint(0, 10, 0),
+	)
+	_, _, err := delaunay.Constrained(dc)
+	assert.NotNil(t, err)
+}
+
+func segment(x1, y1, x2, y2 float64) geom.FullEdge {
+	return geom.FullEdge{geom.NewPoint(x1, y1, 0), geom.NewPoint(x2, y2, 0)}
+}
@@ -0,0 +1,229 @@
+// Package delaunay builds Delaunay triangulations of point sets
+// as dcels, by randomized incremental insertion with edge flips,
+// along with constrained triangulations of dcels and Voronoi
+// diagrams.
+package delaunay
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+)
+
+// Triangulate returns the Delaunay triangulation of pts. Each
+// bounded face of the returned dcel is a triangle, and together
+// they cover the convex hull of pts, whose boundary is a hole in
+// the outer face. Points within ε of each other become one
+// vertex, which is at the first of those points, and vertices
+// are otherwise in the same order as pts, at z = 0. If pts has
+// fewer than three points which are not collinear, Triangulate
+// returns an error.
+func Triangulate(pts []geom.D2) (*dcel.DCEL, error) {
+	return TriangulateWithRand(pts, nil)
+}
+
+// TriangulateWithRand acts as Triangulate, inserting points in
+// an order shuffled by rnd. If rnd is nil, the global source of
+// math/rand is used.
+func TriangulateWithRand(pts []geom.D2, rnd *rand.Rand) (*dcel.DCEL, error) {
+	tr, _, err := build(pts, rnd)
+	if err != nil {
+		return nil, err
+	}
+	dc, _ := tr.dcel(func(*tri) bool { return true }, nil)
+	return dc, nil
+}
+
+// build triangulates pts, returning the triangulation along
+// with the index of each point among its vertices.
+func build(pts []geom.D2, rnd *rand.Rand) (*triangulation, []int, error) {
+	unique, index := dedupe(pts)
+	perm := rand.Perm
+	if rnd != nil {
+		perm = rnd.Perm
+	}
+	order := perm(len(unique))
+
+	// The first three points inserted must not be collinear.
+	tr := &triangulation{pts: unique}
+	if len(order) < 3 {
+		return nil, nil, errors.New("Fewer than three points to triangulate")
+	}
+	found := false
+	for i := 2; i < len(order); i++ {
+		if tr.orient(order[0], order[1], order[i]) != 0 {
+			order[2], order[i] = order[i], order[2]
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil, errors.New("All points to triangulate are collinear")
+	}
+	if tr.orient(order[0], order[1], order[2]) < 0 {
+		order[1], order[2] = order[2], order[1]
+	}
+	tr.init(order[0], order[1], order[2])
+	for _, p := range order[3:] {
+		tr.insert(p)
+	}
+	return tr, index, nil
+}
+
+// dedupe returns pts, in order, without any point within ε
+// of an earlier one, along with the index in that list of
+// each of pts or the point it was merged into.
+func dedupe(pts []geom.D2) ([]geom.D2, []int) {
+	index := make([]int, len(pts))
+	if len(pts) == 0 {
+		return pts, index
+	}
+	idx := make([]int, len(pts))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return intersect.Before(pts[idx[i]], pts[idx[j]])
+	})
+	// Each run of points within ε of each
+	// other is merged into its earliest point.
+	first := make([]int, len(pts))
+	run := []int{idx[0]}
+	keep := idx[0]
+	end := func() {
+		for _, i := range run {
+			first[i] = keep
+		}
+	}
+	for _, i := range idx[1:] {
+		if intersect.Before(pts[keep], pts[i]) {
+			end()
+			run = run[:0]
+			keep = i
+		} else if i < keep {
+			keep = i
+		}
+		run = append(run, i)
+	}
+	end()
+	unique := []geom.D2{}
+	for i, p := range pts {
+		if first[i] == i {
+			index[i] = len(unique)
+			unique = append(unique, p)
+		}
+	}
+	for i := range pts {
+		index[i] = index[first[i]]
+	}
+	return unique, index
+}
+
+// dcel converts the triangulation into a dcel. Each real
+// triangle for which inner returns true becomes a bounded face,
+// which is to the right of its half edges. Everything else is
+// the outer face, whose boundaries are its holes. Edges between
+// two triangles in the outer face are left out, unless they are
+// in fixed. Along with the dcel, dcel returns the face made from
+// each bounded triangle.
+func (tr *triangulation) dcel(inner func(*tri) bool, fixed map[[2]int]bool) (*dcel.DCEL, map[*tri]*dcel.Face) {
+	dc := dcel.New()
+	dc.Vertices = make([]*dcel.Vertex, len(tr.pts))
+	for i, p := range tr.pts {
+		dc.Vertices[i] = dcel.NewVertex(p.X(), p.Y(), 0)
+	}
+	half := make(map[[2]int]*dcel.Edge)
+	edge := func(a, b int) *dcel.Edge {
+		e, ok := half[[2]int{a, b}]
+		if !ok {
+			e = dcel.NewEdge()
+			e.Origin = dc.Vertices[a]
+			t := dcel.NewEdge()
+			t.Origin = dc.Vertices[b]
+			e.SetTwin(t)
+			half[[2]int{a, b}] = e
+			half[[2]int{b, a}] = t
+			dc.HalfEdges = append(dc.HalfEdges, e, t)
+		}
+		return e
+	}
+	faces := make(map[*tri]*dcel.Face)
+	for _, t := range tr.tris {
+		if t.ghost() || !inner(t) {
+			continue
+		}
+		f := dcel.NewFace()
+		dc.Faces = append(dc.Faces, f)
+		faces[t] = f
+		a, b, c := t.v[0], t.v[1], t.v[2]
+		for _, e := range []*dcel.Edge{edge(a, c), edge(c, b), edge(b, a)} {
+			e.Face = f
+		}
+		f.Outer = half[[2]int{a, c}]
+	}
+	for _, t := range tr.tris {
+		if t.ghost() || faces[t] != nil {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			a, b := t.v[(i+1)%3], t.v[(i+2)%3]
+			if fixed[key(a, b)] {
+				edge(a, b)
+			}
+		}
+	}
+
+	// Each vertex's edges are sorted counter-clockwise. A half
+	// edge's face lies to its right, so it is followed by the
+	// edge after its twin around the vertex it leads to.
+	outs := make([][]*dcel.Edge, len(dc.Vertices))
+	index := make(map[*dcel.Vertex]int, len(dc.Vertices))
+	for i, v := range dc.Vertices {
+		index[v] = i
+	}
+	for _, e := range dc.HalfEdges {
+		i := index[e.Origin]
+		outs[i] = append(outs[i], e)
+	}
+	for i, es := range outs {
+		if len(es) == 0 {
+			continue
+		}
+		v := dc.Vertices[i]
+		angles := make(map[*dcel.Edge]float64, len(es))
+		for _, e := range es {
+			angles[e] = math.Atan2(e.Twin.Origin.Y()-v.Y(), e.Twin.Origin.X()-v.X())
+		}
+		sort.Slice(es, func(i, j int) bool {
+			return angles[es[i]] < angles[es[j]]
+		})
+		v.OutEdge = es[0]
+		for j, e := range es {
+			e.Twin.SetNext(es[(j+1)%len(es)])
+		}
+	}
+	outer := dc.Faces[dcel.OUTER_FACE]
+	for _, e := range dc.HalfEdges {
+		if e.Face != nil {
+			continue
+		}
+		outer.Inner = append(outer.Inner, e)
+		for _, e2 := range e.EdgeChain() {
+			e2.Face = outer
+		}
+	}
+	return dc, faces
+}
+
+// key returns the undirected edge between a and b.
+func key(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
@@ -0,0 +1,133 @@
+package delaunay_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/delaunay"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDelaunay checks that dc is a triangulation of
+// its vertices, with no vertex inside the circle
+// through any of its triangles.
+func testDelaunay(t *testing.T, dc *dcel.DCEL) {
+	assert.Empty(t, dc.Validate())
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		vs := f.Vertices()
+		if !assert.Equal(t, 3, len(vs)) {
+			continue
+		}
+		// Faces lie to the right of their edges
+		a, b, c := vs[0], vs[2], vs[1]
+		assert.True(t, geom.Cross2D(a, b, c) > 0)
+		for _, v := range dc.Vertices {
+			assert.False(t, inCircle(a, b, c, v) > 1e-6, "%v in %v", v, vs)
+		}
+	}
+	// The outer face's boundary is convex.
+	outer := dc.Faces[dcel.OUTER_FACE]
+	if assert.Equal(t, 1, len(outer.Inner)) {
+		for _, e := range outer.Inner[0].EdgeChain() {
+			assert.True(t, geom.Cross2D(e.Origin, e.Next.Origin, e.Next.Next.Origin) >= 0)
+		}
+	}
+	found, err := dc.SelfIntersections()
+	assert.Nil(t, err)
+	assert.Empty(t, found)
+}
+
+// inCircle returns a positive value if d is inside the circle
+// through the counter-clockwise triangle abc, relative to
+// the size of that triangle.
+func inCircle(a, b, c, d geom.D2) float64 {
+	adx, ady := a.X()-d.X(), a.Y()-d.Y()
+	bdx, bdy := b.X()-d.X(), b.Y()-d.Y()
+	cdx, cdy := c.X()-d.X(), c.Y()-d.Y()
+	det := (adx*adx+ady*ady)*(bdx*cdy-cdx*bdy) +
+		(bdx*bdx+bdy*bdy)*(cdx*ady-adx*cdy) +
+		(cdx*cdx+cdy*cdy)*(adx*bdy-bdx*ady)
+	area := geom.Cross2D(a, b, c)
+	return det / (area * area)
+}
+
+func randomPts(rnd *rand.Rand, n int, size float64) []geom.D2 {
+	pts := make([]geom.D2, n)
+	for i := range pts {
+		pts[i] = geom.NewPoint(rnd.Float64()*size, rnd.Float64()*size, 0)
+	}
+	return pts
+}
+
+func TestTriangulateRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		pts := randomPts(rnd, 200, 1000)
+		dc, err := delaunay.TriangulateWithRand(pts, rnd)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Equal(t, len(pts), len(dc.Vertices))
+		hull := len(dc.Faces[dcel.OUTER_FACE].Inner[0].EdgeChain())
+		// Euler's formula fixes the number of triangles.
+		assert.Equal(t, 2*len(pts)-2-hull, len(dc.Faces)-1)
+		testDelaunay(t, dc)
+	}
+}
+
+func TestTriangulateGrid(t *testing.T) {
+	// Every four neighboring points of a grid
+	// are cocircular, and its hull has collinear
+	// points along each side.
+	pts := []geom.D2{}
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			pts = append(pts, geom.NewPoint(float64(x), float64(y), 0))
+		}
+	}
+	dc, err := delaunay.TriangulateWithRand(pts, rand.New(rand.NewSource(1)))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 2*9*9, len(dc.Faces)-1)
+	assert.Equal(t, 36, len(dc.Faces[dcel.OUTER_FACE].Inner[0].EdgeChain()))
+	testDelaunay(t, dc)
+}
+
+func TestTriangulateDuplicates(t *testing.T) {
+	pts := []geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(10, 0, 0),
+		geom.NewPoint(0, 1e-9, 0),
+		geom.NewPoint(5, 10, 0),
+		geom.NewPoint(10, 0, 0),
+	}
+	dc, err := delaunay.Triangulate(pts)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 3, len(dc.Vertices))
+	assert.Equal(t, 2, len(dc.Faces))
+	assert.Equal(t, 0.0, dc.Vertices[0].Y())
+	testDelaunay(t, dc)
+}
+
+func TestTriangulateDegenerate(t *testing.T) {
+	_, err := delaunay.Triangulate(nil)
+	assert.NotNil(t, err)
+	_, err = delaunay.Triangulate([]geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(1, 1, 0),
+		geom.NewPoint(1, 1, 0),
+	})
+	assert.NotNil(t, err)
+	_, err = delaunay.Triangulate([]geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(1, 1, 0),
+		geom.NewPoint(2, 2, 0),
+		geom.NewPoint(3, 3, 0),
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,269 @@
+package delaunay
+
+import "github.com/nylen/go-compgeo/geom"
+
+// infinite is the index of the vertex at infinity, which
+// each edge on the convex hull shares a ghost triangle with.
+const infinite = -1
+
+// A tri is a triangle of the triangulation being built, with
+// its vertices in counter-clockwise order. n[i] is the triangle
+// across the edge opposite v[i], from v[i+1] to v[i+2]. A ghost
+// triangle has the infinite vertex, and lies outside the edge
+// between its other two vertices.
+type tri struct {
+	v [3]int
+	n [3]*tri
+}
+
+func (t *tri) ghost() bool {
+	return t.v[0] == infinite || t.v[1] == infinite || t.v[2] == infinite
+}
+
+// index returns the index of v in t, or -1 if t does not have v.
+func (t *tri) index(v int) int {
+	for i := 0; i < 3; i++ {
+		if t.v[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// rotate shifts the vertices and neighbors of t so
+// that what was at index i is at index 0.
+func (t *tri) rotate(i int) {
+	v, n := t.v, t.n
+	for j := 0; j < 3; j++ {
+		t.v[j] = v[(i+j)%3]
+		t.n[j] = n[(i+j)%3]
+	}
+}
+
+// edge returns the index of the vertex of t opposite
+// the edge which runs from a to b, or -1 if there is none.
+func (t *tri) edge(a, b int) int {
+	for i := 0; i < 3; i++ {
+		if t.v[(i+1)%3] == a && t.v[(i+2)%3] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// link sets the ith neighbor of t to u, and sets t as
+// u's neighbor across the same edge.
+func (t *tri) link(i int, u *tri) {
+	t.n[i] = u
+	u.n[u.edge(t.v[(i+2)%3], t.v[(i+1)%3])] = t
+}
+
+// A triangulation is a Delaunay triangulation of pts, built
+// up one point at a time.
+type triangulation struct {
+	pts  []geom.D2
+	tris []*tri
+	// last is the most recently made triangle,
+	// which searches for new points start from.
+	last *tri
+	// at holds a triangle around each vertex, once
+	// every point has been inserted.
+	at []*tri
+	// made holds the edges flipped in while adding
+	// a constraint, which may not be Delaunay.
+	made [][2]int
+}
+
+func (tr *triangulation) newTri(a, b, c int) *tri {
+	t := &tri{v: [3]int{a, b, c}}
+	tr.tris = append(tr.tris, t)
+	tr.last = t
+	return t
+}
+
+func (tr *triangulation) orient(a, b, c int) float64 {
+	return geom.Orient2D(tr.pts[a], tr.pts[b], tr.pts[c])
+}
+
+// init starts the triangulation with the counter-clockwise
+// triangle abc and the three ghost triangles around it.
+func (tr *triangulation) init(a, b, c int) {
+	t := tr.newTri(a, b, c)
+	ga := tr.newTri(c, b, infinite)
+	gb := tr.newTri(a, c, infinite)
+	gc := tr.newTri(b, a, infinite)
+	t.link(0, ga)
+	t.link(1, gb)
+	t.link(2, gc)
+	ga.link(0, gc)
+	ga.link(1, gb)
+	gb.link(1, gc)
+	tr.last = t
+}
+
+// insert adds the point p to the triangulation,
+// flipping edges until it is Delaunay again.
+func (tr *triangulation) insert(p int) {
+	t := tr.locate(p)
+	var stack []*tri
+	if !t.ghost() {
+		for i := 0; i < 3; i++ {
+			if tr.orient(t.v[(i+1)%3], t.v[(i+2)%3], p) == 0 {
+				stack = tr.splitEdge(t, i, p)
+				break
+			}
+		}
+	}
+	if stack == nil {
+		stack = tr.split(t, p)
+	}
+	for len(stack) != 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		u := t.n[0]
+		u.rotate(u.edge(t.v[2], t.v[1]))
+		if tr.illegal(t, u) {
+			tr.flip(t, u)
+			stack = append(stack, t, u)
+		}
+	}
+}
+
+// locate returns a real triangle containing p, or a ghost
+// triangle whose edge p lies strictly outside of, by walking
+// across edges toward p.
+func (tr *triangulation) locate(p int) *tri {
+	t := tr.last
+	for steps := 0; steps < len(tr.tris); steps++ {
+		next := tr.step(t, p, steps)
+		if next == nil {
+			return t
+		}
+		t = next
+	}
+	// The walk should not cycle, but if rounding
+	// makes it do so, every triangle is checked.
+	for _, t := range tr.tris {
+		if tr.step(t, p, 0) == nil {
+			return t
+		}
+	}
+	return tr.last
+}
+
+// step returns the neighbor of t to walk to toward p,
+// or nil if t is the triangle locate is looking for.
+// Edges are checked starting from a different one
+// each step, so that the walk does not cycle.
+func (tr *triangulation) step(t *tri, p, steps int) *tri {
+	if t.ghost() {
+		for i := 0; i < 3; i++ {
+			if t.v[i] != infinite {
+				continue
+			}
+			if tr.orient(t.v[(i+1)%3], t.v[(i+2)%3], p) > 0 {
+				return nil
+			}
+			return t.n[i]
+		}
+	}
+	for j := 0; j < 3; j++ {
+		i := (j + steps) % 3
+		if tr.orient(t.v[(i+1)%3], t.v[(i+2)%3], p) < 0 {
+			return t.n[i]
+		}
+	}
+	return nil
+}
+
+// split splits t into three triangles around p, which lies
+// inside it, returning them with p first.
+func (tr *triangulation) split(t *tri, p int) []*tri {
+	v, n := t.v, t.n
+	t0 := t
+	t0.v = [3]int{p, v[1], v[2]}
+	t1 := tr.newTri(p, v[2], v[0])
+	t2 := tr.newTri(p, v[0], v[1])
+	t0.link(0, n[0])
+	t1.link(0, n[1])
+	t2.link(0, n[2])
+	t0.n[1], t0.n[2] = t1, t2
+	t1.n[1], t1.n[2] = t2, t0
+	t2.n[1], t2.n[2] = t0, t1
+	return []*tri{t0, t1, t2}
+}
+
+// splitEdge splits t and its neighbor across the edge
+// opposite t.v[i] into four triangles around p, which lies
+// on that edge, returning them with p first.
+func (tr *triangulation) splitEdge(t *tri, i, p int) []*tri {
+	t.rotate(i)
+	u := t.n[0]
+	u.rotate(u.edge(t.v[2], t.v[1]))
+	a, b, c := t.v[0], t.v[1], t.v[2]
+	d := u.v[0]
+	ta, tb := t.n[1], t.n[2]
+	ub, uc := u.n[1], u.n[2]
+
+	t1 := t
+	t1.v = [3]int{p, c, a}
+	t2 := tr.newTri(p, a, b)
+	u1 := u
+	u1.v = [3]int{p, b, d}
+	u2 := tr.newTri(p, d, c)
+	t1.link(0, ta)
+	t2.link(0, tb)
+	u1.link(0, ub)
+	u2.link(0, uc)
+	t1.n[1], t1.n[2] = t2, u2
+	t2.n[1], t2.n[2] = u1, t1
+	u1.n[1], u1.n[2] = u2, t2
+	u2.n[1], u2.n[2] = t1, u1
+	return []*tri{t1, t2, u1, u2}
+}
+
+// illegal reports whether the edge between t, whose first
+// vertex is the point just inserted, and u, whose first vertex
+// is across that edge from it, should be flipped. A ghost
+// triangle's circle is the half plane outside its edge.
+func (tr *triangulation) illegal(t, u *tri) bool {
+	p := t.v[0]
+	if u.v[0] == infinite {
+		return false
+	}
+	if u.ghost() {
+		for i := 0; i < 3; i++ {
+			if u.v[i] == infinite {
+				return tr.orient(u.v[(i+1)%3], u.v[(i+2)%3], p) > 0
+			}
+		}
+	}
+	return tr.inCircle(u.v[0], u.v[1], u.v[2], p) > 0
+}
+
+// inCircle returns a positive value if d lies inside the circle
+// through the counter-clockwise triangle abc, a negative value if
+// it lies outside, and zero if it lies on it.
+func (tr *triangulation) inCircle(a, b, c, d int) float64 {
+	return geom.InCircle(tr.pts[a], tr.pts[b], tr.pts[c], tr.pts[d])
+}
+
+// flip replaces the edge between t = (p, x, y) and
+// u = (q, y, x) with the edge from p to q, leaving
+// t = (p, x, q) and u = (p, q, y).
+func (tr *triangulation) flip(t, u *tri) {
+	p, x, y := t.v[0], t.v[1], t.v[2]
+	q := u.v[0]
+	a, b := t.n[1], t.n[2]
+	c, d := u.n[1], u.n[2]
+	t.v = [3]int{p, x, q}
+	u.v = [3]int{p, q, y}
+	t.link(0, c)
+	u.link(0, d)
+	t.link(2, b)
+	u.link(1, a)
+	t.n[1], u.n[2] = u, t
+	if tr.at != nil {
+		tr.at[p], tr.at[x], tr.at[q], tr.at[y] = t, t, t, u
+	}
+}
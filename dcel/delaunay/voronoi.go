@@ -0,0 +1,197 @@
+package delaunay
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+)
+
+// Voronoi returns the Voronoi diagram of sites, clipped to bounds,
+// which is found as the dual of their Delaunay triangulation. Each
+// bounded face of the returned dcel is the part of one site's cell
+// which lies inside bounds, and the outer face is everything
+// outside bounds. Along with it, Voronoi returns the cell of each
+// of sites, in the same order. Sites within ε of each other share
+// a cell, and sites whose cells do not reach into bounds have no
+// cell. Only the x and y values of bounds are considered.
+func Voronoi(sites []geom.D2, bounds geom.Span) (*dcel.DCEL, []*dcel.Face, error) {
+	if len(sites) == 0 {
+		return nil, nil, compgeo.EmptyError{}
+	}
+	min, max := bounds.At(geom.SPAN_MIN).(geom.Point), bounds.At(geom.SPAN_MAX).(geom.Point)
+	if !intersect.Before(min, max) || geom.F64eq(min.X(), max.X()) || geom.F64eq(min.Y(), max.Y()) {
+		return nil, nil, errors.New("Voronoi bounds have no area")
+	}
+	box := []geom.Point{
+		geom.NewPoint(min.X(), min.Y(), 0),
+		geom.NewPoint(max.X(), min.Y(), 0),
+		geom.NewPoint(max.X(), max.Y(), 0),
+		geom.NewPoint(min.X(), max.Y(), 0),
+	}
+	segs := []geom.FullEdge{}
+	for i, p := range box {
+		segs = append(segs, geom.FullEdge{p, box[(i+1)%4]})
+	}
+	clip := func(p geom.Point, dx, dy, t0, t1 float64) {
+		if fe, ok := clipLine(p, dx, dy, t0, t1, min, max); ok {
+			segs = append(segs, fe)
+		}
+	}
+
+	// adj holds the sites whose cells share an edge with each site.
+	var adj [][]int
+	unique, index := dedupe(sites)
+	tr, _, err := build(unique, nil)
+	if err != nil {
+		// The sites are collinear, so their cells are
+		// strips between the bisectors of neighboring sites.
+		order := make([]int, len(unique))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return intersect.Before(unique[order[i]], unique[order[j]])
+		})
+		adj = make([][]int, len(unique))
+		for i := 1; i < len(order); i++ {
+			a, b := order[i-1], order[i]
+			adj[a] = append(adj[a], b)
+			adj[b] = append(adj[b], a)
+			pa, pb := unique[a], unique[b]
+			mid := geom.NewPoint((pa.X()+pb.X())/2, (pa.Y()+pb.Y())/2, 0)
+			clip(mid, pa.Y()-pb.Y(), pb.X()-pa.X(), math.Inf(-1), math.Inf(1))
+		}
+	} else {
+		adj = make([][]int, len(unique))
+		centers := make(map[*tri]geom.Point, len(tr.tris))
+		center := func(t *tri) geom.Point {
+			c, ok := centers[t]
+			if !ok {
+				c = tr.circumcenter(t)
+				centers[t] = c
+			}
+			return c
+		}
+		for _, t := range tr.tris {
+			if t.ghost() {
+				continue
+			}
+			for i, u := range t.n {
+				a, b := t.v[(i+1)%3], t.v[(i+2)%3]
+				if !u.ghost() && a > b {
+					continue
+				}
+				adj[a] = append(adj[a], b)
+				adj[b] = append(adj[b], a)
+				c := center(t)
+				if u.ghost() {
+					// The cells of sites on the hull are
+					// unbounded, and their shared edge
+					// leaves to the right of ab.
+					pa, pb := tr.pts[a], tr.pts[b]
+					clip(c, pb.Y()-pa.Y(), pa.X()-pb.X(), 0, math.Inf(1))
+					continue
+				}
+				c2 := center(u)
+				clip(c, c2.X()-c.X(), c2.Y()-c.Y(), 0, 1)
+			}
+		}
+	}
+
+	dc, err := dcel.FromSegments(segs)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Each face is convex, so the average of its corners is
+	// inside it, and it is the cell of the site nearest that
+	// point. The nearest site is found by walking from site to
+	// site toward it, which always finds it on a Delaunay
+	// triangulation.
+	faces := make([]*dcel.Face, len(unique))
+	at := 0
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		vs := f.Vertices()
+		var x, y float64
+		for _, v := range vs {
+			x += v.X()
+			y += v.Y()
+		}
+		p := geom.NewPoint(x/float64(len(vs)), y/float64(len(vs)), 0)
+		at = nearest(unique, adj, at, p)
+		faces[at] = f
+	}
+	cells := make([]*dcel.Face, len(sites))
+	for i := range sites {
+		cells[i] = faces[index[i]]
+	}
+	return dc, cells, nil
+}
+
+// nearest returns the site nearest p, walking from
+// start to whichever neighbor of each site is nearer.
+func nearest(sites []geom.D2, adj [][]int, start int, p geom.D2) int {
+	dist := func(i int) float64 {
+		dx, dy := sites[i].X()-p.X(), sites[i].Y()-p.Y()
+		return dx*dx + dy*dy
+	}
+	at, d := start, dist(start)
+	for moved := true; moved; {
+		moved = false
+		for _, j := range adj[at] {
+			if d2 := dist(j); d2 < d {
+				at, d = j, d2
+				moved = true
+			}
+		}
+	}
+	return at
+}
+
+// circumcenter returns the center of the circle
+// through the vertices of the real triangle t.
+func (tr *triangulation) circumcenter(t *tri) geom.Point {
+	a, b, c := tr.pts[t.v[0]], tr.pts[t.v[1]], tr.pts[t.v[2]]
+	bx, by := b.X()-a.X(), b.Y()-a.Y()
+	cx, cy := c.X()-a.X(), c.Y()-a.Y()
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return geom.NewPoint(a.X()+(cy*b2-by*c2)/d, a.Y()+(bx*c2-cx*b2)/d, 0)
+}
+
+// clipLine returns the part of the line p + t(dx, dy), for t
+// from t0 to t1, which lies inside the box from min to max,
+// if any of it does.
+func clipLine(p geom.Point, dx, dy, t0, t1 float64, min, max geom.Point) (geom.FullEdge, bool) {
+	for _, c := range [][3]float64{
+		{dx, p.X(), min.X()}, {-dx, -p.X(), -max.X()},
+		{dy, p.Y(), min.Y()}, {-dy, -p.Y(), -max.Y()},
+	} {
+		// The line is inside this side of the box
+		// where c[1] + t*c[0] >= c[2].
+		d, v, lim := c[0], c[1], c[2]
+		if d == 0 {
+			if v < lim {
+				return geom.FullEdge{}, false
+			}
+			continue
+		}
+		t := (lim - v) / d
+		if d > 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+	}
+	if t0 >= t1 {
+		return geom.FullEdge{}, false
+	}
+	return geom.FullEdge{
+		geom.NewPoint(p.X()+t0*dx, p.Y()+t0*dy, 0),
+		geom.NewPoint(p.X()+t1*dx, p.Y()+t1*dy, 0),
+	}, true
+}
@@ -0,0 +1,97 @@
+package delaunay_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/delaunay"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVoronoi checks that random points inside bounds lie in
+// the cell of the site nearest them.
+func testVoronoi(t *testing.T, sites []geom.D2, bounds geom.Span, limit int) (*dcel.DCEL, []*dcel.Face) {
+	dc, cells, err := delaunay.Voronoi(sites, bounds)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Empty(t, dc.Validate())
+	assert.Equal(t, len(sites), len(cells))
+	min, d := bounds.At(geom.SPAN_MIN).(geom.Point), bounds.Diff()
+	for i := 0; i < limit; i++ {
+		p := geom.NewPoint(min.X()+rand.Float64()*d.X(), min.Y()+rand.Float64()*d.Y(), 0)
+		best, dist := 0, -1.0
+		for j, s := range sites {
+			dx, dy := s.X()-p.X(), s.Y()-p.Y()
+			if dist < 0 || dx*dx+dy*dy < dist {
+				best, dist = j, dx*dx+dy*dy
+			}
+		}
+		if assert.NotNil(t, cells[best]) {
+			assert.True(t, cells[best].Contains(p), "%v not in the cell of %v", p, sites[best])
+		}
+	}
+	return dc, cells
+}
+
+func TestVoronoiRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	bounds := geom.NewSpan(geom.NewPoint(0, 0, 0), geom.NewPoint(1000, 1000, 0))
+	for i := 0; i < 10; i++ {
+		sites := randomPts(rnd, 100, 1000)
+		dc, _ := testVoronoi(t, sites, bounds, 500)
+		assert.Equal(t, len(sites), len(dc.Faces)-1)
+	}
+}
+
+func TestVoronoiGrid(t *testing.T) {
+	sites := []geom.D2{}
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			sites = append(sites, geom.NewPoint(float64(x)+.5, float64(y)+.5, 0))
+		}
+	}
+	bounds := geom.NewSpan(geom.NewPoint(0, 0, 0), geom.NewPoint(5, 5, 0))
+	dc, cells := testVoronoi(t, sites, bounds, 500)
+	assert.Equal(t, 26, len(dc.Faces))
+	assert.Equal(t, 36, len(dc.Vertices))
+	for _, c := range cells {
+		assert.Equal(t, 4, len(c.Vertices()))
+	}
+}
+
+func TestVoronoiOutside(t *testing.T) {
+	sites := []geom.D2{
+		geom.NewPoint(1, 1, 0),
+		geom.NewPoint(3, 2, 0),
+		geom.NewPoint(2, 3, 0),
+		// This site's cell reaches into the bounds,
+		geom.NewPoint(2, 4.8, 0),
+		// but this one's does not.
+		geom.NewPoint(2, 20, 0),
+		geom.NewPoint(3, 2, 0),
+	}
+	bounds := geom.NewSpan(geom.NewPoint(0, 0, 0), geom.NewPoint(4, 4, 0))
+	dc, cells := testVoronoi(t, sites, bounds, 500)
+	assert.Equal(t, 5, len(dc.Faces))
+	assert.NotNil(t, cells[3])
+	assert.Nil(t, cells[4])
+	assert.Equal(t, cells[1], cells[5])
+}
+
+func TestVoronoiCollinear(t *testing.T) {
+	bounds := geom.NewSpan(geom.NewPoint(0, 0, 0), geom.NewPoint(10, 10, 0))
+	dc, _ := testVoronoi(t, []geom.D2{geom.NewPoint(5, 5, 0)}, bounds, 100)
+	assert.Equal(t, 2, len(dc.Faces))
+	dc, _ = testVoronoi(t, []geom.D2{
+		geom.NewPoint(1, 1, 0),
+		geom.NewPoint(5, 5, 0),
+		geom.NewPoint(3, 3, 0),
+	}, bounds, 500)
+	assert.Equal(t, 4, len(dc.Faces))
+
+	_, _, err := delaunay.Voronoi(nil, bounds)
+	assert.NotNil(t, err)
+}
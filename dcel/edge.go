@@ -186,50 +186,34 @@ func (e *Edge) Compare(i interface{}) search.CompareResult {
 }
 
 // IsClockwise returns whether a given set of
-// edges is clockwise or not.
-// Method credit: lhf on stackOverflow
-// https://math.stackexchange.com/questions/340830
+// edges is clockwise or not, in a system where
+// y increases downward.
+// We use the sign of the area enclosed by the edges,
+// rather than the turn at a single extreme vertex,
+// as that turn is unreliable when the extreme vertex's
+// neighbors are nearly colinear with it.
 func (e *Edge) IsClockwise() (bool, error) {
 	if e == nil {
 		return false, compgeo.BadEdgeError{}
 	}
 	start := e
-	lowest := e
-	// Find the highest, rightmost point.
-	// We find the highest because the axes
-	// in this system are flipped so y increases
-	// going downward. Ultimately as long as we
-	// are consistent with one approach this does
-	// not change anything.
-	e = e.Next
-	for e != start {
-		if e == nil || e.Next == nil {
+	o := start.Origin
+	area := 0.0
+	for {
+		if e.Next == nil {
 			return false, compgeo.BadEdgeError{}
 		}
-		if lowest.Origin.Greater2D(e.Origin).Eq(lowest.Origin) {
-			lowest = e
-		}
+		// Points are taken relative to the start of the
+		// chain to limit rounding error.
+		a := e.Origin
+		b := e.Next.Origin
+		area += (a.X()-o.X())*(b.Y()-o.Y()) - (b.X()-o.X())*(a.Y()-o.Y())
 		e = e.Next
+		if e == start {
+			break
+		}
 	}
-	if lowest.Prev == nil {
-		return false, compgeo.BadEdgeError{}
-	}
-	p := lowest.Prev.Origin.Point
-	c := lowest.Origin.Point
-	n := lowest.Next.Origin.Point
-
-	cross := (p[0] * c[1]) - (c[0] * p[1]) +
-		(p[1] * n[0]) - (p[0] * n[1]) +
-		(c[0] * n[1]) - (n[0] * c[1])
-
-	// We assume the points are not colinear,
-	// as they must not be. If they were,
-	// one of lowest's neighbors would be
-	// higher than lowest.
-	if cross > 0 {
-		return true, nil
-	}
-	return false, nil
+	return area > 0, nil
 }
 
 // Flip converts edge and all that share a
@@ -7,10 +7,15 @@ import (
 )
 
 // A Face points to the edges on its inner and
-// outer portions. Any given face may have either
-// of these values be nil, but never both.
+// outer portions. Outer is any edge on the face's outer
+// boundary, and is nil only for the outer face. Inner holds
+// an edge on each of the face's holes, the boundaries of
+// regions enclosed by the face which are not part of it.
+// For the outer face, these are the outer boundaries of
+// each component of the DCEL.
 type Face struct {
-	Inner, Outer *Edge
+	Outer *Edge
+	Inner []*Edge
 }
 
 // NewFace returns a null-initialized Face.
@@ -18,11 +23,10 @@ func NewFace() *Face {
 	return &Face{}
 }
 
-// Vertices wraps around a face and
-// finds all vertices that border it.
+// Vertices wraps around a face and finds all vertices
+// on its outer boundary. Vertices on its holes are
+// not included.
 func (f *Face) Vertices() []*Vertex {
-	// Inner is not populated by anything as of this writing.
-
 	pts := []*Vertex{}
 	e := f.Outer
 	for e != nil && e.Next != f.Outer {
@@ -35,35 +39,56 @@ func (f *Face) Vertices() []*Vertex {
 	return pts
 }
 
+// Boundaries returns an edge on each of f's boundary
+// chains, its outer boundary first, if it has one,
+// followed by its holes.
+func (f *Face) Boundaries() []*Edge {
+	chains := make([]*Edge, 0, len(f.Inner)+1)
+	if f.Outer != nil {
+		chains = append(chains, f.Outer)
+	}
+	return append(chains, f.Inner...)
+}
+
 // Contains returns whether a point lies inside f.
 // We cannot assume that f is convex, or anything
 // besides some polygon. That leaves us with a rather
 // complex form of PIP--
+// Crossings are counted over every boundary of f, so
+// points within f's holes are not contained. The outer
+// face contains every point outside of its holes.
 func (f *Face) Contains(p geom.D2) bool {
 	x := p.X()
 	y := p.Y()
 	contains := false
-	bounds := f.Bounds()
-	min := bounds.At(0).(geom.D2)
-	max := bounds.At(1).(geom.D2)
-	if x < min.Val(0) || x > max.Val(0) ||
-		y < min.Val(1) || y > max.Val(1) {
-		return contains
+	if f.Outer != nil {
+		bounds := f.Bounds()
+		min := bounds.At(0).(geom.D2)
+		max := bounds.At(1).(geom.D2)
+		if x < min.Val(0) || x > max.Val(0) ||
+			y < min.Val(1) || y > max.Val(1) {
+			return contains
+		}
 	}
 
-	e1 := f.Outer.Prev
-	e2 := f.Outer
-	for {
-		if (e2.Y() > y) != (e1.Y() > y) {
-			if x < (e1.X()-e2.X())*(y-e2.Y())/(e1.Y()-e2.Y())+e2.X() {
-				contains = !contains
+	for _, start := range f.Boundaries() {
+		e1 := start.Prev
+		e2 := start
+		for {
+			if (e2.Y() > y) != (e1.Y() > y) {
+				if x < (e1.X()-e2.X())*(y-e2.Y())/(e1.Y()-e2.Y())+e2.X() {
+					contains = !contains
+				}
+			}
+			e1 = e1.Next
+			e2 = e2.Next
+			if e1 == start.Prev {
+				break
 			}
 		}
-		e1 = e1.Next
-		e2 = e2.Next
-		if e1 == f.Outer.Prev {
-			break
-		}
+	}
+	if f.Outer == nil {
+		return !contains
 	}
 	return contains
 }
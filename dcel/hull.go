@@ -0,0 +1,38 @@
+package dcel
+
+import (
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// ConvexHull returns the corners of the convex hull of pts, as
+// geom.ConvexHull finds them, along with a dcel whose one face is
+// that hull. If the hull has fewer than three corners, ConvexHull
+// returns them with a BadVertexError.
+func ConvexHull(pts []geom.D2) ([]geom.D2, *DCEL, error) {
+	return hullDCEL(geom.ConvexHull(pts))
+}
+
+// PolylineHull acts as ConvexHull for the simple
+// polyline pts, using geom.PolylineHull.
+func PolylineHull(pts []geom.D2) ([]geom.D2, *DCEL, error) {
+	return hullDCEL(geom.PolylineHull(pts))
+}
+
+// Hull returns the convex hull of the outer boundary of
+// f, as PolylineHull finds it. Holes in f are ignored.
+func (f *Face) Hull() ([]geom.D2, *DCEL, error) {
+	vs := f.Vertices()
+	pts := make([]geom.D2, len(vs))
+	for i, v := range vs {
+		pts[i] = v
+	}
+	return PolylineHull(pts)
+}
+
+func hullDCEL(hull []geom.D2) ([]geom.D2, *DCEL, error) {
+	if len(hull) < 3 {
+		return hull, nil, compgeo.BadVertexError{}
+	}
+	return hull, Polygon(hull), nil
+}
@@ -0,0 +1,306 @@
+package dcel
+
+import (
+	"math"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// ConvexHull3D returns the convex hull of pts as a closed polytope.
+// Each face of the returned dcel past the outer face, which is left
+// empty, is one flat side of the hull, and its edges run counter-
+// clockwise when seen from outside the hull, as in OFF files. The
+// vertices of the dcel are the corners of the hull, in the order they
+// appear in pts. Points are added one at a time, so building the hull
+// takes O(n^2) time at worst. If pts all lie on one plane,
+// ConvexHull3D returns a BadVertexError.
+func ConvexHull3D(pts []geom.D3) (*DCEL, error) {
+	h, err := newHull3D(pts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pts {
+		h.add(i)
+	}
+	return h.dcel(), nil
+}
+
+// A facet is a triangle on a hull3D, whose vertices
+// run counter-clockwise seen from outside the hull.
+type facet struct {
+	v    [3]int
+	dead bool
+}
+
+// hull3D holds a convex hull of some of pts as triangles.
+type hull3D struct {
+	pts    []geom.D3
+	facets []*facet
+	// edges holds the facet to the left of each directed edge.
+	edges map[[2]int]*facet
+	used  []bool
+}
+
+func newHull3D(pts []geom.D3) (*hull3D, error) {
+	// The first tetrahedron is made of points which are as
+	// far apart as can be easily found, so that later points
+	// are not measured against slivers.
+	if len(pts) < 4 {
+		return nil, compgeo.BadVertexError{}
+	}
+	best := func(score func(geom.D3) float64) int {
+		j, max := 0, 0.0
+		for i, p := range pts {
+			if s := math.Abs(score(p)); s > max {
+				j, max = i, s
+			}
+		}
+		return j
+	}
+	a := 0
+	b := best(func(p geom.D3) float64 {
+		d := sub3D(p, pts[a])
+		return dot3D(d, d)
+	})
+	c := best(func(p geom.D3) float64 {
+		n := normal3D(pts[a], pts[b], p)
+		return dot3D(n, n)
+	})
+	d := best(func(p geom.D3) float64 {
+		return orient3D(pts[a], pts[b], pts[c], p)
+	})
+	if orient3D(pts[a], pts[b], pts[c], pts[d]) == 0 {
+		return nil, compgeo.BadVertexError{}
+	}
+	if orient3D(pts[a], pts[b], pts[c], pts[d]) > 0 {
+		b, c = c, b
+	}
+	h := &hull3D{
+		pts:   pts,
+		edges: make(map[[2]int]*facet),
+		used:  make([]bool, len(pts)),
+	}
+	for _, v := range [][3]int{{a, b, c}, {a, d, b}, {b, d, c}, {c, d, a}} {
+		h.facet(v)
+	}
+	return h, nil
+}
+
+// facet adds a facet with vertices v to h.
+func (h *hull3D) facet(v [3]int) {
+	f := &facet{v: v}
+	h.facets = append(h.facets, f)
+	for i := 0; i < 3; i++ {
+		h.edges[[2]int{v[i], v[(i+1)%3]}] = f
+		h.used[v[i]] = true
+	}
+}
+
+// visible reports whether pts[p] is strictly
+// outside the plane of f.
+func (h *hull3D) visible(f *facet, p int) bool {
+	return orient3D(h.pts[f.v[0]], h.pts[f.v[1]], h.pts[f.v[2]], h.pts[p]) > 0
+}
+
+// add extends h to include pts[p], replacing the facets p can
+// see with a cone of facets from p to the edge of what it sees.
+func (h *hull3D) add(p int) {
+	if h.used[p] {
+		return
+	}
+	var start *facet
+	for _, f := range h.facets {
+		if !f.dead && h.visible(f, p) {
+			start = f
+			break
+		}
+	}
+	if start == nil {
+		return
+	}
+	// The facets p sees are found by spreading from one of
+	// them, so that they are connected even under rounding.
+	seen := map[*facet]bool{start: true}
+	stack := []*facet{start}
+	horizon := [][2]int{}
+	for len(stack) != 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i := 0; i < 3; i++ {
+			e := [2]int{f.v[i], f.v[(i+1)%3]}
+			g := h.edges[[2]int{e[1], e[0]}]
+			if seen[g] {
+				continue
+			}
+			if h.visible(g, p) {
+				seen[g] = true
+				stack = append(stack, g)
+			} else {
+				horizon = append(horizon, e)
+			}
+		}
+	}
+	for f := range seen {
+		f.dead = true
+		for i := 0; i < 3; i++ {
+			delete(h.edges, [2]int{f.v[i], f.v[(i+1)%3]})
+		}
+	}
+	for _, e := range horizon {
+		h.facet([3]int{e[0], e[1], p})
+	}
+	live := h.facets[:0]
+	for _, f := range h.facets {
+		if !f.dead {
+			live = append(live, f)
+		}
+	}
+	h.facets = live
+}
+
+// dcel joins the facets of h which lie on one plane
+// into faces, and returns the dcel of those faces.
+func (h *hull3D) dcel() *DCEL {
+	// Facets are grouped by joining each with any
+	// neighbor whose far vertex is on its plane.
+	group := make(map[*facet]*facet, len(h.facets))
+	var find func(*facet) *facet
+	find = func(f *facet) *facet {
+		g, ok := group[f]
+		if !ok || g == f {
+			return f
+		}
+		group[f] = find(g)
+		return group[f]
+	}
+	for _, f := range h.facets {
+		a, b, c := h.pts[f.v[0]], h.pts[f.v[1]], h.pts[f.v[2]]
+		n := normal3D(a, b, c)
+		for i := 0; i < 3; i++ {
+			g := h.edges[[2]int{f.v[(i+1)%3], f.v[i]}]
+			var far int
+			for _, v := range g.v {
+				if v != f.v[i] && v != f.v[(i+1)%3] {
+					far = v
+				}
+			}
+			d := sub3D(h.pts[far], a)
+			if geom.F64eq(dot3D(n, d)/math.Sqrt(dot3D(n, n)*dot3D(d, d)), 0) {
+				group[find(f)] = find(g)
+			}
+		}
+	}
+
+	// Each group's boundary is the edges of its facets
+	// whose neighbors are in other groups, and from each
+	// vertex on it there is one such edge onward.
+	order := []*facet{}
+	next := make(map[*facet]map[int]int)
+	for _, f := range h.facets {
+		r := find(f)
+		if next[r] == nil {
+			next[r] = make(map[int]int)
+			order = append(order, r)
+		}
+		for i := 0; i < 3; i++ {
+			a, b := f.v[i], f.v[(i+1)%3]
+			if find(h.edges[[2]int{b, a}]) != r {
+				next[r][a] = b
+			}
+		}
+	}
+	loops := make([][]int, len(order))
+	faces := make([]int, len(h.pts))
+	for i, r := range order {
+		start := -1
+		for v := range next[r] {
+			if start < 0 || v < start {
+				start = v
+			}
+		}
+		for v := start; ; {
+			loops[i] = append(loops[i], v)
+			faces[v]++
+			v = next[r][v]
+			if v == start {
+				break
+			}
+		}
+	}
+
+	// Points along the edges of faces, or inside them, may
+	// have been corners before later points were added, and
+	// are left out. Each corner is on at least three faces.
+	dc := New()
+	vertices := make([]*Vertex, len(h.pts))
+	for i, p := range h.pts {
+		if faces[i] >= 3 {
+			vertices[i] = PointToVertex(p)
+			dc.Vertices = append(dc.Vertices, vertices[i])
+		}
+	}
+	edges := make(map[[2]int]*Edge)
+	for _, loop := range loops {
+		corners := []int{}
+		for _, v := range loop {
+			if vertices[v] != nil {
+				corners = append(corners, v)
+			}
+		}
+		f := NewFace()
+		dc.Faces = append(dc.Faces, f)
+		var prev *Edge
+		for i, a := range corners {
+			b := corners[(i+1)%len(corners)]
+			e, ok := edges[[2]int{a, b}]
+			if !ok {
+				e = NewEdge()
+				e.Origin = vertices[a]
+				t := NewEdge()
+				t.Origin = vertices[b]
+				e.SetTwin(t)
+				edges[[2]int{a, b}], edges[[2]int{b, a}] = e, t
+				dc.HalfEdges = append(dc.HalfEdges, e, t)
+			}
+			e.Face = f
+			vertices[a].OutEdge = e
+			if prev != nil {
+				prev.SetNext(e)
+			} else {
+				f.Outer = e
+			}
+			prev = e
+		}
+		prev.SetNext(f.Outer)
+	}
+	return dc
+}
+
+func sub3D(a, b geom.D3) [3]float64 {
+	return [3]float64{a.X() - b.X(), a.Y() - b.Y(), a.Z() - b.Z()}
+}
+
+func dot3D(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// normal3D returns the cross product of b-a and c-a,
+// which points out of the side of the plane through
+// a, b and c from which they run counter-clockwise.
+func normal3D(a, b, c geom.D3) [3]float64 {
+	u, v := sub3D(b, a), sub3D(c, a)
+	return [3]float64{
+		u[1]*v[2] - u[2]*v[1],
+		u[2]*v[0] - u[0]*v[2],
+		u[0]*v[1] - u[1]*v[0],
+	}
+}
+
+// orient3D returns a positive value if d lies on the side of the
+// plane through a, b and c from which they run counter-clockwise,
+// a negative value if it lies on the other side, and zero if it is
+// on the plane. Its magnitude is six times the volume of abcd.
+func orient3D(a, b, c, d geom.D3) float64 {
+	return dot3D(normal3D(a, b, c), sub3D(d, a))
+}
@@ -0,0 +1,133 @@
+package dcel_test
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/off"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// orient returns six times the volume of abcd, which is
+// positive if d is outside a face running a, b, c.
+func orient(a, b, c, d geom.D3) float64 {
+	ux, uy, uz := b.X()-a.X(), b.Y()-a.Y(), b.Z()-a.Z()
+	vx, vy, vz := c.X()-a.X(), c.Y()-a.Y(), c.Z()-a.Z()
+	wx, wy, wz := d.X()-a.X(), d.Y()-a.Y(), d.Z()-a.Z()
+	return (uy*vz-uz*vy)*wx + (uz*vx-ux*vz)*wy + (ux*vy-uy*vx)*wz
+}
+
+// testHull3D checks that dc is a closed, convex polytope,
+// with pts inside or on each of its faces.
+func testHull3D(t *testing.T, dc *dcel.DCEL, pts []geom.D3) {
+	assert.Empty(t, dc.Validate())
+	outer := dc.Faces[dcel.OUTER_FACE]
+	assert.Nil(t, outer.Outer)
+	assert.Empty(t, outer.Inner)
+	// V - E + F = 2
+	assert.Equal(t, 2, len(dc.Vertices)-len(dc.HalfEdges)/2+len(dc.Faces)-1)
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		vs := f.Vertices()
+		if !assert.True(t, len(vs) >= 3) {
+			continue
+		}
+		for i := 2; i < len(vs); i++ {
+			for _, p := range pts {
+				assert.True(t, orient(vs[0], vs[i-1], vs[i], p) <= 1e-6,
+					"%v is outside the hull", p)
+			}
+		}
+	}
+}
+
+func TestConvexHull3DCube(t *testing.T) {
+	pts := []geom.D3{}
+	// Points inside the cube, on its faces and along
+	// its edges come before its corners.
+	for _, x := range []float64{.5, 0, 1} {
+		for _, y := range []float64{.5, 0, 1} {
+			for _, z := range []float64{.5, 0, 1} {
+				pts = append(pts, geom.NewPoint(x, y, z))
+			}
+		}
+	}
+	dc, err := dcel.ConvexHull3D(pts)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	testHull3D(t, dc, pts)
+	assert.Equal(t, 8, len(dc.Vertices))
+	assert.Equal(t, 7, len(dc.Faces))
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		assert.Equal(t, 4, len(f.Vertices()))
+	}
+}
+
+func TestConvexHull3DRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		pts := make([]geom.D3, 200)
+		for j := range pts {
+			pts[j] = geom.NewPoint(rnd.Float64()*100, rnd.Float64()*100, rnd.Float64()*100)
+		}
+		dc, err := dcel.ConvexHull3D(pts)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		testHull3D(t, dc, pts)
+		for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+			assert.Equal(t, 3, len(f.Vertices()))
+		}
+	}
+}
+
+func TestConvexHull3DOFF(t *testing.T) {
+	pts := []geom.D3{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(2, 0, 0),
+		geom.NewPoint(0, 2, 0),
+		geom.NewPoint(0, 0, 2),
+		geom.NewPoint(2, 2, 2),
+		geom.NewPoint(.5, .5, .5),
+	}
+	dc, err := dcel.ConvexHull3D(pts)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	file := filepath.Join(t.TempDir(), "hull.off")
+	assert.Nil(t, off.Save(dc).WriteFile(file))
+	dc2, err := off.Load(file)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	testHull3D(t, dc2, pts)
+	assert.Equal(t, len(dc.Vertices), len(dc2.Vertices))
+	assert.Equal(t, len(dc.HalfEdges), len(dc2.HalfEdges))
+	for i, v := range dc.Vertices {
+		assert.Equal(t, v.Point, dc2.Vertices[i].Point)
+	}
+	for i, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		vs, vs2 := f.Vertices(), dc2.Faces[i+1].Vertices()
+		if assert.Equal(t, len(vs), len(vs2)) {
+			for j, v := range vs {
+				assert.Equal(t, v.Point, vs2[j].Point)
+			}
+		}
+	}
+}
+
+func TestConvexHull3DFlat(t *testing.T) {
+	_, err := dcel.ConvexHull3D([]geom.D3{
+		geom.NewPoint(0, 0, 1),
+		geom.NewPoint(1, 0, 1),
+		geom.NewPoint(0, 1, 1),
+		geom.NewPoint(1, 1, 1),
+	})
+	assert.Equal(t, compgeo.BadVertexError{}, err)
+	_, err = dcel.ConvexHull3D([]geom.D3{geom.NewPoint(0, 0, 1)})
+	assert.Equal(t, compgeo.BadVertexError{}, err)
+}
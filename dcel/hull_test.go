@@ -0,0 +1,66 @@
+package dcel_test
+
+import (
+	"testing"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygon(t *testing.T) {
+	ccw := []geom.D2{
+		geom.NewPoint(0, 0, 1),
+		geom.NewPoint(4, 0, 2),
+		geom.NewPoint(4, 4, 3),
+		geom.NewPoint(2, 1, 4),
+		geom.NewPoint(0, 4, 5),
+	}
+	cw := []geom.D2{ccw[4], ccw[3], ccw[2], ccw[1], ccw[0]}
+	for _, in := range [][]geom.D2{ccw, cw} {
+		dc := dcel.Polygon(in)
+		assert.Empty(t, dc.Validate())
+		assert.Equal(t, 2, len(dc.Faces))
+		assert.Equal(t, 5, len(dc.Faces[1].Vertices()))
+		assert.Equal(t, in[0].(geom.Point), dc.Vertices[0].Point)
+		assert.True(t, dc.Faces[1].Contains(geom.NewPoint(1, 2, 0)))
+		assert.False(t, dc.Faces[1].Contains(geom.NewPoint(2, 3, 0)))
+	}
+}
+
+func TestConvexHullDCEL(t *testing.T) {
+	hull, dc, err := dcel.ConvexHull([]geom.D2{
+		geom.NewPoint(2, 2, 0),
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(4, 1, 0),
+		geom.NewPoint(1, 4, 0),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(hull))
+	assert.Empty(t, dc.Validate())
+	assert.Equal(t, 3, len(dc.Vertices))
+
+	_, _, err = dcel.ConvexHull([]geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(1, 1, 0),
+	})
+	assert.Equal(t, compgeo.BadVertexError{}, err)
+}
+
+func TestFaceHull(t *testing.T) {
+	dc := dcel.Random2DDCELWithSeed(1000, 20, 1)
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		hull, hdc, err := f.Hull()
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Empty(t, hdc.Validate())
+		for _, v := range f.Vertices() {
+			for i, a := range hull {
+				b := hull[(i+1)%len(hull)]
+				assert.True(t, geom.Cross2D(a, b, v) >= -1e-9)
+			}
+		}
+	}
+}
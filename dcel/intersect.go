@@ -0,0 +1,58 @@
+package dcel
+
+import "github.com/nylen/go-compgeo/geom/intersect"
+
+// SelfIntersections returns the points at which the edges of dc
+// meet other than at a vertex they share, such as where edges
+// cross, where an edge ends on another, where edges overlap, or
+// where distinct vertices lie at the same point. A dcel with any
+// such points does not describe a planar subdivision. Each
+// intersection's Segments index the edges of dc, so that i refers
+// to HalfEdges[2i] and its twin. Only the x and y values of dc
+// are considered.
+func (dc *DCEL) SelfIntersections() ([]intersect.Intersection, error) {
+	segs, _, err := dc.FullEdges()
+	if err != nil {
+		return nil, err
+	}
+	found, err := intersect.Find(segs)
+	if err != nil {
+		return nil, err
+	}
+	bad := []intersect.Intersection{}
+	for _, in := range found {
+		if !dc.sharedVertex(in.Segments) {
+			bad = append(bad, in)
+		}
+	}
+	return bad, nil
+}
+
+// sharedVertex reports whether every edge in edges, indexed
+// as FullEdges indexes them, has one endpoint at the same
+// vertex and no two of them share their other endpoint.
+func (dc *DCEL) sharedVertex(edges []int) bool {
+	e := dc.HalfEdges[2*edges[0]]
+	for _, v := range []*Vertex{e.Origin, e.Twin.Origin} {
+		others := make(map[*Vertex]bool, len(edges))
+		for _, i := range edges {
+			e2 := dc.HalfEdges[2*i]
+			var other *Vertex
+			if e2.Origin == v {
+				other = e2.Twin.Origin
+			} else if e2.Twin.Origin == v {
+				other = e2.Origin
+			} else {
+				break
+			}
+			if others[other] {
+				break
+			}
+			others[other] = true
+		}
+		if len(others) == len(edges) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package dcel_test
+
+import (
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfIntersectionsPlanar(t *testing.T) {
+	for i := int64(0); i < 10; i++ {
+		dc := dcel.Random2DDCELWithSeed(1000, 20, i)
+		found, err := dc.SelfIntersections()
+		assert.Nil(t, err)
+		assert.Empty(t, found)
+	}
+	dc, _, err := dcel.Overlay(dcel.Rect(0, 0, 10, 10), dcel.Rect(5, 5, 10, 10))
+	assert.Nil(t, err)
+	found, err := dc.SelfIntersections()
+	assert.Nil(t, err)
+	assert.Empty(t, found)
+}
+
+func TestSelfIntersectionsCross(t *testing.T) {
+	dc := dcel.FourPoint(
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(10, 10, 0),
+		geom.NewPoint(10, 0, 0),
+		geom.NewPoint(0, 10, 0),
+	)
+	found, err := dc.SelfIntersections()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(found)) {
+		assert.True(t, found[0].Eq(geom.NewPoint(5, 5, 0)))
+		assert.Equal(t, 2, len(found[0].Segments))
+	}
+}
+
+func TestSelfIntersectionsOverlap(t *testing.T) {
+	// The second edge doubles back along the first.
+	dc := dcel.FourPoint(
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(10, 0, 0),
+		geom.NewPoint(5, 0, 0),
+		geom.NewPoint(0, 10, 0),
+	)
+	found, err := dc.SelfIntersections()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(found)) {
+		assert.True(t, found[0].Eq(geom.NewPoint(5, 0, 0)))
+		assert.Equal(t, 3, len(found[0].Segments))
+	}
+
+	// Distinct vertices at the same point
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.Vertices[2].Mult(0, 0)
+	dc.Vertices[2].Mult(1, 0)
+	found, err = dc.SelfIntersections()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, found)
+}
@@ -0,0 +1,31 @@
+package off_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel/off"
+)
+
+// FuzzRead feeds arbitrary input to Read, which should return
+// an error for anything it cannot load, rather than panic or
+// loop forever. Inputs which once did are kept in testdata.
+func FuzzRead(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "*.off"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dc, err := off.Read(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		dc.Validate()
+	})
+}
@@ -3,10 +3,12 @@ package off
 import (
 	"bufio"
 	"io"
+	"math"
 	"os"
 
 	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
 )
 
 // Decode converts an OFF struct into a dcel.
@@ -126,6 +128,9 @@ func Read(f io.Reader) (*dcel.DCEL, error) {
 	}
 	numVertices := counts[0]
 	numFaces := counts[1]
+	if numVertices < 0 || numFaces < 0 {
+		return nil, compgeo.TypeError{}
+	}
 
 	dc := new(dcel.DCEL)
 
@@ -136,7 +141,9 @@ func Read(f io.Reader) (*dcel.DCEL, error) {
 	var edge *dcel.Edge
 	var face *dcel.Face
 
-	dc.Vertices = make([]*dcel.Vertex, numVertices)
+	// The counts are not trusted to size anything up front,
+	// as a malformed header could claim any number of lines.
+	dc.Vertices = make([]*dcel.Vertex, 0)
 
 	// Read numVertices lines as vertices
 	// Each dcel.Vertex is represented as three numbers,
@@ -146,13 +153,13 @@ func Read(f io.Reader) (*dcel.DCEL, error) {
 		if err != nil {
 			return nil, err
 		}
-		dc.Vertices[i] = dcel.NewVertex(fs[0], fs[1], fs[2])
+		dc.Vertices = append(dc.Vertices, dcel.NewVertex(fs[0], fs[1], fs[2]))
 	}
 
 	var vi int
 
 	edges := make([]*dcel.Edge, 0)
-	dc.Faces = make([]*dcel.Face, numFaces+1)
+	dc.Faces = make([]*dcel.Face, 1)
 	auxData := make(map[*dcel.Vertex][]*dcel.Edge)
 
 	// Faces are represented by a count of edges followed
@@ -165,9 +172,12 @@ func Read(f io.Reader) (*dcel.DCEL, error) {
 		if err != nil {
 			return nil, err
 		}
+		if !validFace(fs, numVertices) {
+			return nil, compgeo.TypeError{}
+		}
 
 		face = new(dcel.Face)
-		dc.Faces[i] = face
+		dc.Faces = append(dc.Faces, face)
 
 		edge = new(dcel.Edge)
 		edges = append(edges, edge)
@@ -272,12 +282,18 @@ func decode(dc *dcel.DCEL, edges []*dcel.Edge,
 		if edge.Twin.Next == nil {
 			continue //?
 		}
+		// Around a vertex where more than two boundary edges
+		// meet, this walk can cycle, so it is cut short.
 		prev = edge.Twin.Next.Twin
-		for prev.Next != nil { // Could infinite loop, apparently??
+		for steps := 0; prev.Next != nil; steps++ {
+			if steps > len(edges) {
+				return nil, compgeo.NotManifoldError{}
+			}
 			prev = prev.Next.Twin
 		}
-		prev.Next = edge
+		prev.SetNext(edge)
 	}
+	nest(dc, edges, outerFaceList)
 	dc.HalfEdges = make([]*dcel.Edge, 0)
 	ei := 0
 	marked := make(map[*dcel.Edge]bool)
@@ -293,3 +309,138 @@ func decode(dc *dcel.DCEL, edges []*dcel.Edge,
 
 	return dc, nil
 }
+
+// nest finds the holes of dc's faces. Each connected component
+// of dc is bounded by a chain of edges on the outer face. If dc is
+// planar and that chain lies inside another face, the component
+// was written as a face nested inside that face, and the chain
+// becomes one of that face's holes. Otherwise it is kept as a
+// hole in the outer face.
+func nest(dc *dcel.DCEL, edges, outerFaceList []*dcel.Edge) {
+	outerFace := dc.Faces[dcel.OUTER_FACE]
+	chains := [][]*dcel.Edge{}
+	seen := make(map[*dcel.Edge]bool)
+	for _, e := range outerFaceList {
+		if seen[e] {
+			continue
+		}
+		chain, ok := cycle(e, len(outerFaceList))
+		for _, e2 := range chain {
+			seen[e2] = true
+		}
+		if ok {
+			chains = append(chains, chain)
+		}
+	}
+	if !flat(dc) {
+		for _, chain := range chains {
+			outerFace.Inner = append(outerFace.Inner, chain[0])
+		}
+		return
+	}
+
+	// Label each vertex with its component.
+	parent := make(map[*dcel.Vertex]*dcel.Vertex)
+	var find func(*dcel.Vertex) *dcel.Vertex
+	find = func(v *dcel.Vertex) *dcel.Vertex {
+		p, ok := parent[v]
+		if !ok || p == v {
+			return v
+		}
+		parent[v] = find(p)
+		return parent[v]
+	}
+	for _, e := range edges {
+		parent[find(e.Origin)] = find(e.Twin.Origin)
+	}
+
+	// A component may have more than one chain on the outer
+	// face, if it surrounds some area which is not one of its
+	// faces. The chain around the outside of the component is
+	// the one with the largest bounds.
+	exterior := make(map[*dcel.Vertex][]*dcel.Edge)
+	for _, chain := range chains {
+		c := find(chain[0].Origin)
+		if ext, ok := exterior[c]; !ok || boundsArea(chain) > boundsArea(ext) {
+			exterior[c] = chain
+		}
+	}
+
+	for _, chain := range chains {
+		c := find(chain[0].Origin)
+		// Of the faces of other components around chain,
+		// the innermost has the smallest bounds.
+		var best *dcel.Face
+		if exterior[c][0] == chain[0] {
+			bestArea := math.Inf(1)
+			for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+				if find(f.Outer.Origin) == c {
+					continue
+				}
+				outer := f.Outer.EdgeChain()
+				if a := boundsArea(outer); a < bestArea && f.Contains(chain[0].Origin) {
+					best = f
+					bestArea = a
+				}
+			}
+		}
+		if best == nil {
+			outerFace.Inner = append(outerFace.Inner, chain[0])
+			continue
+		}
+		best.Inner = append(best.Inner, chain[0])
+		for _, e := range chain {
+			e.Face = best
+		}
+	}
+}
+
+// validFace reports whether fs, the vertex indices of a face,
+// are each of the n vertices read, and make up a polygon: there
+// are at least three, and no two in a row are the same.
+func validFace(fs []int, n int) bool {
+	if len(fs) < 3 {
+		return false
+	}
+	for j, vi := range fs {
+		if vi < 0 || vi >= n || vi == fs[(j+1)%len(fs)] {
+			return false
+		}
+	}
+	return true
+}
+
+// cycle returns the chain of edges following e, and whether
+// that chain returns to e within limit edges.
+func cycle(e *dcel.Edge, limit int) ([]*dcel.Edge, bool) {
+	chain := []*dcel.Edge{e}
+	for e2 := e.Next; e2 != e; e2 = e2.Next {
+		if e2 == nil || len(chain) >= limit {
+			return chain, false
+		}
+		chain = append(chain, e2)
+	}
+	return chain, true
+}
+
+// flat reports whether every vertex of dc lies on the
+// same z plane, such that dc is a planar subdivision.
+func flat(dc *dcel.DCEL) bool {
+	for _, v := range dc.Vertices {
+		if v.Z() != dc.Vertices[0].Z() {
+			return false
+		}
+	}
+	return true
+}
+
+// boundsArea returns the area of the
+// bounding box of the edges in chain.
+func boundsArea(chain []*dcel.Edge) float64 {
+	sp := geom.NewSpan()
+	for _, e := range chain {
+		sp = sp.Expand(e.Origin)
+	}
+	d := sp.Diff()
+	return d.X() * d.Y()
+}
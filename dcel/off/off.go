@@ -20,6 +20,9 @@ type OFF struct {
 	NumVertices, NumFaces, NumEdges int
 	Vertices                        []Vertex
 	Faces                           []Face
+	// Comments are written as '#' lines following
+	// the OFF header, and are ignored when read.
+	Comments []string
 }
 
 type Face []int
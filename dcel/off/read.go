@@ -8,11 +8,23 @@ import (
 	compgeo "github.com/nylen/go-compgeo"
 )
 
+// scan advances s past any blank or comment lines
+// to the next line holding data.
+func scan(s *bufio.Scanner) bool {
+	for s.Scan() {
+		t := strings.TrimSpace(s.Text())
+		if t != "" && !strings.HasPrefix(t, "#") {
+			return true
+		}
+	}
+	return false
+}
+
 func readIntLine(s *bufio.Scanner, l int) ([]int, error) {
 	var err error
 	out := make([]int, l)
 
-	if !s.Scan() {
+	if !scan(s) {
 		return out, compgeo.TypeError{}
 	}
 
@@ -35,7 +47,7 @@ func readFloat64Line(s *bufio.Scanner, l int) ([]float64, error) {
 	var err error
 	out := make([]float64, l)
 
-	if !s.Scan() {
+	if !scan(s) {
 		return out, compgeo.TypeError{}
 	}
 
@@ -57,23 +69,23 @@ func readFloat64Line(s *bufio.Scanner, l int) ([]float64, error) {
 // The number of elements in this line is defined by the first value.
 func readIntsLineNoLength(s *bufio.Scanner) (int, []int, error) {
 	var err error
-	if !s.Scan() {
+	if !scan(s) {
 		return 0, make([]int, 0), compgeo.TypeError{}
 	}
 
 	ints := strings.Split(s.Text(), " ")
 
 	length, err := strconv.Atoi(ints[0])
-	if err != nil {
+	if err != nil || length < 0 {
 		return 0, nil, compgeo.TypeError{}
 	}
 
-	out := make([]int, length)
-
 	if len(ints) < (length + 1) {
 		return 0, nil, compgeo.TypeError{}
 	}
 
+	out := make([]int, length)
+
 	for i := 0; i < length; i++ {
 		out[i], err = strconv.Atoi(ints[i+1])
 		if err != nil {
@@ -38,6 +38,11 @@ func Save(dc *dcel.DCEL) OFF {
 func (of OFF) WriteFile(relPath string) error {
 	// This could be made much faster using the bufio package
 	bData := []byte("OFF\n")
+	for _, c := range of.Comments {
+		bData = append(bData, "# "...)
+		bData = append(bData, c...)
+		bData = append(bData, '\n')
+	}
 	bData = append(bData, strconv.Itoa(of.NumVertices)...)
 	bData = append(bData, ' ')
 	bData = append(bData, strconv.Itoa(of.NumFaces)...)
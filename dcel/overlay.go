@@ -0,0 +1,137 @@
+package dcel
+
+import (
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+)
+
+// Overlay returns the subdivision formed by laying a over b,
+// whose edges are those of a and b, split wherever they cross.
+// Along with it, Overlay returns a map from each face in the
+// overlay to the faces of a and b which contain it, in that
+// order. Edges are found to cross by a line sweep over both
+// dcels. Only the x and y values of a and b are considered,
+// and the overlay's vertices lie at z = 0.
+func Overlay(a, b *DCEL) (*DCEL, map[*Face][2]*Face, error) {
+	inputs := [2]*DCEL{a, b}
+	segs := []geom.FullEdge{}
+	// srcs holds the half edge which runs from the first
+	// point of each segment to its second, and from
+	// which input it came.
+	srcs := []*Edge{}
+	from := []int{}
+	for k, dc := range inputs {
+		for i := 0; i+1 < len(dc.HalfEdges); i += 2 {
+			e := dc.HalfEdges[i]
+			segs = append(segs, geom.FullEdge{
+				geom.NewPoint(e.Origin.X(), e.Origin.Y(), 0),
+				geom.NewPoint(e.Twin.Origin.X(), e.Twin.Origin.Y(), 0),
+			})
+			srcs = append(srcs, e)
+			from = append(from, k)
+		}
+	}
+	pts, err := intersect.Sweep(segs)
+	if err != nil {
+		return nil, nil, err
+	}
+	dc, edgeSrcs := arrange(segs, pts)
+
+	// The faces each new half edge lies in are those
+	// to the right of the input half edges under it.
+	right := [2]map[*Edge]bool{faceSides(a), faceSides(b)}
+	faceMap := make(map[*Face][2]*Face, len(dc.Faces))
+	known := make(map[*Face][2]bool, len(dc.Faces))
+	set := func(f *Face, k int, f2 *Face) {
+		fs, ks := faceMap[f], known[f]
+		fs[k], ks[k] = f2, true
+		faceMap[f], known[f] = fs, ks
+	}
+	covered := make(map[*Edge][2]bool, len(dc.HalfEdges))
+	for i, ss := range edgeSrcs {
+		e := dc.HalfEdges[2*i]
+		for _, s := range ss {
+			k := from[s]
+			h := srcs[s]
+			if intersect.Before(segs[s][1], segs[s][0]) {
+				h = h.Twin
+			}
+			for _, pair := range [2][2]*Edge{{e, h}, {e.Twin, h.Twin}} {
+				f := pair[1].Face
+				if !right[k][pair[1]] {
+					f = pair[1].Twin.Face
+				}
+				set(pair[0].Face, k, f)
+				c := covered[pair[0]]
+				c[k] = true
+				covered[pair[0]] = c
+			}
+		}
+	}
+	for k, in := range inputs {
+		var outer *Face
+		if len(in.Faces) > OUTER_FACE {
+			outer = in.Faces[OUTER_FACE]
+		}
+		set(dc.Faces[OUTER_FACE], k, outer)
+	}
+
+	// Faces with no edges from one input lie in the same face
+	// of that input as their neighbors across those edges.
+	queue := append([]*Face{}, dc.Faces...)
+	for len(queue) != 0 {
+		f := queue[0]
+		queue = queue[1:]
+		for k := range inputs {
+			if !known[f][k] {
+				continue
+			}
+			for _, start := range f.Boundaries() {
+				for _, e := range start.EdgeChain() {
+					f2 := e.Twin.Face
+					if covered[e][k] || known[f2][k] {
+						continue
+					}
+					set(f2, k, faceMap[f][k])
+					queue = append(queue, f2)
+				}
+			}
+		}
+	}
+	return dc, faceMap, nil
+}
+
+// faceSides reports, for each half edge of dc, whether its face
+// lies to its right. This is found separately for each chain of
+// edges, as a face is to the right of a clockwise outer boundary
+// or a counter-clockwise hole.
+func faceSides(dc *DCEL) map[*Edge]bool {
+	right := make(map[*Edge]bool, len(dc.HalfEdges))
+	seen := make(map[*Edge]bool, len(dc.HalfEdges))
+	for _, e := range dc.HalfEdges {
+		if seen[e] {
+			continue
+		}
+		chain := []*Edge{}
+		outer := false
+		for e2 := e; e2 != nil && !seen[e2]; e2 = e2.Next {
+			seen[e2] = true
+			chain = append(chain, e2)
+			if e2.Face != nil && e2 == e2.Face.Outer {
+				outer = true
+			}
+		}
+		clockwise, err := e.IsClockwise()
+		isRight := true
+		if err == nil {
+			// IsClockwise measures with y increasing downward,
+			// so it reports whether e's chain runs
+			// counter-clockwise with y increasing upward.
+			isRight = clockwise != outer
+		}
+		for _, e2 := range chain {
+			right[e2] = isRight
+		}
+	}
+	return right
+}
@@ -0,0 +1,84 @@
+package dcel_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// locate returns the face of dc containing p, or
+// the outer face if no other face contains it.
+func locate(dc *dcel.DCEL, p geom.D2) *dcel.Face {
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		if f.Contains(p) {
+			return f
+		}
+	}
+	return dc.Faces[dcel.OUTER_FACE]
+}
+
+// testOverlay checks that points in each face of the overlay
+// of a and b lie in the faces of a and b it is mapped to.
+func testOverlay(t *testing.T, a, b *dcel.DCEL, size float64, limit int) *dcel.DCEL {
+	dc, faceMap, err := dcel.Overlay(a, b)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Empty(t, dc.Validate())
+	for _, f := range dc.Faces {
+		_, ok := faceMap[f]
+		assert.True(t, ok)
+	}
+	for i := 0; i < limit; i++ {
+		p := geom.NewPoint(rand.Float64()*size, rand.Float64()*size, 0)
+		fs := faceMap[locate(dc, p)]
+		if !assert.Equal(t, a.ScanFaces(locate(a, p)), a.ScanFaces(fs[0])) ||
+			!assert.Equal(t, b.ScanFaces(locate(b, p)), b.ScanFaces(fs[1])) {
+			t.Log("Error point:", p)
+		}
+	}
+	return dc
+}
+
+func TestOverlayRects(t *testing.T) {
+	a := dcel.Rect(0, 0, 10, 10)
+	b := dcel.Rect(5, 5, 10, 10)
+	dc := testOverlay(t, a, b, 20, 1000)
+	// The outer face, each rectangle less the other,
+	// and where they overlap
+	assert.Equal(t, 4, len(dc.Faces))
+	assert.Equal(t, 10, len(dc.Vertices))
+}
+
+func TestOverlaySelf(t *testing.T) {
+	// Every edge of an overlay of a dcel on
+	// itself lies on an edge of the other.
+	a := dcel.Random2DDCELWithSeed(1000, 25, 1)
+	dc := testOverlay(t, a, a, 1000, 1000)
+	assert.Equal(t, len(a.Faces), len(dc.Faces))
+	assert.Equal(t, len(a.HalfEdges), len(dc.HalfEdges))
+}
+
+func TestOverlayNested(t *testing.T) {
+	a := dcel.Rect(0, 0, 100, 100)
+	b := dcel.Rect(40, 40, 20, 20)
+	dc := testOverlay(t, a, b, 120, 1000)
+	assert.Equal(t, 3, len(dc.Faces))
+	// b's square is a hole in what is left of a
+	assert.Equal(t, 1, len(locate(dc, geom.NewPoint(10, 10, 0)).Inner))
+}
+
+func TestOverlayRandom(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		a := dcel.Random2DDCELWithSeed(1000, 15, int64(2*i))
+		b := dcel.Random2DDCELWithSeed(1000, 15, int64(2*i+1))
+		for _, v := range b.Vertices {
+			v.Add(0, 123.4)
+			v.Add(1, 56.7)
+		}
+		testOverlay(t, a, b, 1200, 200)
+	}
+}
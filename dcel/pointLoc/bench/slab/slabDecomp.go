@@ -34,26 +34,13 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 	// faces.
 	faceEdgeMap := make(map[*dcel.Edge]*dcel.Face)
 	for _, f := range dc.Faces {
-		// walk each face
-		e := f.Outer
-		if e != nil {
+		// walk each of the face's boundaries
+		for _, start := range f.Boundaries() {
+			e := start
 			if e.Origin.X() < e.Twin.Origin.X() {
 				faceEdgeMap[e] = f
 			}
-			for e = e.Next; e != f.Outer; e = e.Next {
-				// This edge points right,
-				// Then this face lies beneath e.
-				if e.Origin.X() < e.Twin.Origin.X() {
-					faceEdgeMap[e] = f
-				}
-			}
-		}
-		e = f.Inner
-		if e != nil {
-			if e.Origin.X() < e.Twin.Origin.X() {
-				faceEdgeMap[e] = f
-			}
-			for e = e.Next; e != f.Inner; e = e.Next {
+			for e = e.Next; e != start; e = e.Next {
 				// This edge points right,
 				// Then this face lies beneath e.
 				if e.Origin.X() < e.Twin.Origin.X() {
@@ -3,88 +3,61 @@
 package bruteForce
 
 import (
-	"image/color"
-
 	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
 	"github.com/nylen/go-compgeo/dcel/pointLoc"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
 	"github.com/nylen/go-compgeo/geom"
 )
 
 // PlumbLine method is a name for a linear PIP check that
 // shoots a ray out and checks how many times that ray intersects
 // a polygon. The variation on a DCEL will iteratively perform
-// plumb line on each face of the DCEL.
-func PlumbLine(dc *dcel.DCEL) pointLoc.LocatesPoints {
-	return &Iterator{dc}
+// plumb line on each face of the DCEL. If a Tracer is given,
+// each face checked by a query is traced to it.
+func PlumbLine(dc *dcel.DCEL, tr ...pointLoc.Tracer) pointLoc.LocatesPoints {
+	return &Iterator{dc, pointLoc.OptionalTracer(tr)}
 }
 
-// Iterator is a simple dcel wrapper for the following pointLocate method
+// Iterator is a simple dcel wrapper for the following pointLocate method.
+// It only reads from its DCEL, and may be queried concurrently.
 type Iterator struct {
 	*dcel.DCEL
+	tracer pointLoc.Tracer
 }
 
 // PointLocate on an iterator performs plumb line on each
-// of a DCEL's faces in order.
+// of a DCEL's faces in order. Points on the boundaries of
+// faces, which plumb line may count outside all of them,
+// are placed in a face touching them.
 func (i *Iterator) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
 	}
 	p := geom.NewPoint(vs[0], vs[1], 0)
-	containFn := contains
-	if visualize.VisualCh != nil {
-		containFn = VisualizeContains
-	}
 	for j := 1; j < len(i.Faces); j++ {
 		f := i.Faces[j]
-		if containFn(f, p) {
+		i.tracer.FaceChecked(f)
+		if f.Contains(p) {
 			return f, nil
 		}
 	}
-	return nil, nil
-}
-
-func contains(f *dcel.Face, p geom.D2) bool {
-	return f.Contains(p)
+	outer := i.Faces[dcel.OUTER_FACE]
+	return pointLoc.Classify(p, outer, i.HalfEdges...).Touching(outer), nil
 }
 
-// VisualizeContains returns whether a point lies inside f.
-// We cannot assume that f is convex, or anything
-// besides some polygon. That leaves us with a rather
-// complex form of PIP--
-// It also sends visualization singals while doing this.
-func VisualizeContains(f *dcel.Face, p geom.D2) bool {
-	x := p.X()
-	y := p.Y()
-	contains := false
-	bounds := f.Bounds()
-	min := bounds.At(0).(geom.D2)
-	max := bounds.At(1).(geom.D2)
-	visualize.HighlightColor = color.RGBA{0, 0, 255, 255}
-	visualize.DrawFace(f)
-	if x < min.Val(0) || x > max.Val(0) ||
-		y < min.Val(1) || y > max.Val(1) {
-		return contains
+// Locate returns where (x, y) lies, telling points on vertices
+// and edges apart from points inside faces, by checking every
+// edge.
+func (i *Iterator) Locate(x, y float64) (pointLoc.Location, error) {
+	f, err := i.PointLocate(x, y)
+	if err != nil {
+		return pointLoc.Location{}, err
 	}
+	return pointLoc.Classify(geom.NewPoint(x, y, 0), f, i.HalfEdges...), nil
+}
 
-	e1 := f.Outer.Prev
-	e2 := f.Outer
-	for {
-		visualize.HighlightColor = color.RGBA{0, 0, 255, 255}
-		visualize.DrawLine(e2.Origin, e1.Origin)
-		if (e2.Y() > y) != (e1.Y() > y) {
-			if x < (e1.X()-e2.X())*(y-e2.Y())/(e1.Y()-e2.Y())+e2.X() {
-				visualize.HighlightColor = color.RGBA{0, 255, 0, 255}
-				visualize.DrawLine(e2.Origin, e1.Origin)
-				contains = !contains
-			}
-		}
-		e1 = e1.Next
-		e2 = e2.Next
-		if e1 == f.Outer.Prev {
-			break
-		}
-	}
-	return contains
+// BatchLocate point locates each of pts concurrently,
+// returning the face containing each point in order.
+func (i *Iterator) BatchLocate(pts []geom.D2) ([]*dcel.Face, error) {
+	return pointLoc.BatchLocate(i, pts)
 }
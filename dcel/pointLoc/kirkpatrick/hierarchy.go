@@ -0,0 +1,332 @@
+package kirkpatrick
+
+import (
+	"math"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// maxDegree is the greatest degree a vertex can have
+// and still be removed from a level of the hierarchy.
+const maxDegree = 8
+
+// A triangle is a triangle in the current level of the
+// hierarchy, with its vertices counter-clockwise.
+type triangle struct {
+	vs   [3]int
+	tree *Tree
+	dead bool
+}
+
+// A hierarchy holds the coarsest triangulation built so far,
+// as the triangles around each vertex.
+type hierarchy struct {
+	pts   []geom.D2
+	index map[*dcel.Vertex]int
+	stars [][]*triangle
+}
+
+func newHierarchy(dc *dcel.DCEL) *hierarchy {
+	h := &hierarchy{
+		pts:   make([]geom.D2, len(dc.Vertices)),
+		index: make(map[*dcel.Vertex]int),
+		stars: make([][]*triangle, len(dc.Vertices)),
+	}
+	for i, v := range dc.Vertices {
+		h.pts[i] = v
+		h.index[v] = i
+	}
+	return h
+}
+
+// addFace adds the triangles of f, which should already be
+// a triangle, to the finest level of the hierarchy.
+func (h *hierarchy) addFace(f *dcel.Face, inFace *dcel.Face) {
+	if f.Outer == nil {
+		return
+	}
+	vs := []int{}
+	for _, e := range f.Outer.EdgeChain() {
+		vs = append(vs, h.index[e.Origin])
+	}
+	// Faces which could not be triangulated, for having no area,
+	// are fanned into triangles with no area.
+	for i := 1; i+1 < len(vs); i++ {
+		h.add([3]int{vs[0], vs[i], vs[i+1]}, &Tree{face: inFace})
+	}
+}
+
+func (h *hierarchy) add(vs [3]int, tr *Tree) *triangle {
+	if geom.Cross2D(h.pts[vs[0]], h.pts[vs[1]], h.pts[vs[2]]) < 0 {
+		vs[1], vs[2] = vs[2], vs[1]
+	}
+	t := &triangle{vs: vs, tree: tr}
+	for i, v := range vs {
+		tr.tri[i] = h.pts[v]
+		h.stars[v] = append(h.stars[v], t)
+	}
+	return t
+}
+
+// star returns the live triangles around v.
+func (h *hierarchy) star(v int) []*triangle {
+	live := h.stars[v][:0]
+	for _, t := range h.stars[v] {
+		if !t.dead {
+			live = append(live, t)
+		}
+	}
+	h.stars[v] = live
+	return live
+}
+
+// link returns the vertices around v, counter-clockwise, and
+// whether they loop all the way around v. Vertices on the bounds
+// of the triangulation do not loop. If v's triangles do not form
+// a fan around v, ok is false.
+func (h *hierarchy) link(v int) (link []int, cyclic, ok bool) {
+	star := h.star(v)
+	if len(star) == 0 {
+		return nil, false, false
+	}
+	next := make(map[int]int)
+	hasPrev := make(map[int]bool)
+	for _, t := range star {
+		i := 0
+		for t.vs[i] != v {
+			i++
+		}
+		a, b := t.vs[(i+1)%3], t.vs[(i+2)%3]
+		if _, dup := next[a]; dup || hasPrev[b] {
+			return nil, false, false
+		}
+		next[a] = b
+		hasPrev[b] = true
+	}
+	start := -1
+	for _, t := range star {
+		for _, a := range t.vs {
+			if _, ok := next[a]; ok && !hasPrev[a] {
+				if start != -1 && start != a {
+					return nil, false, false
+				}
+				start = a
+			}
+		}
+	}
+	cyclic = start == -1
+	if cyclic {
+		i := 0
+		for star[0].vs[i] != v {
+			i++
+		}
+		start = star[0].vs[(i+1)%3]
+	}
+	link = []int{start}
+	for cur := start; ; {
+		n, ok := next[cur]
+		if !ok || (cyclic && n == start) {
+			break
+		}
+		link = append(link, n)
+		if len(link) > len(star)+1 {
+			return nil, false, false
+		}
+		cur = n
+	}
+	if cyclic && len(link) != len(star) ||
+		!cyclic && len(link) != len(star)+1 {
+		return nil, false, false
+	}
+	return link, cyclic, true
+}
+
+// removable returns the polygon left behind if v were removed, if
+// v can be removed. Vertices on the bounds of the triangulation
+// can only be removed if they lie on a straight part of the bounds,
+// so the shape of the triangulation does not change.
+func (h *hierarchy) removable(v int) ([]int, bool) {
+	link, cyclic, ok := h.link(v)
+	if !ok || len(link) > maxDegree {
+		return nil, false
+	}
+	if !cyclic {
+		a, b, c := h.pts[link[0]], h.pts[v], h.pts[link[len(link)-1]]
+		if geom.Cross2D(a, b, c) != 0 ||
+			(a.X()-b.X())*(c.X()-b.X())+(a.Y()-b.Y())*(c.Y()-b.Y()) >= 0 {
+			return nil, false
+		}
+	}
+	return link, true
+}
+
+// reduce removes an independent set of low degree vertices
+// from the hierarchy, retriangulating the holes they leave
+// behind. It returns whether any vertices were removed.
+func (h *hierarchy) reduce() bool {
+	marked := make([]bool, len(h.pts))
+	removed := false
+	for v := range h.pts {
+		if marked[v] {
+			continue
+		}
+		poly, ok := h.removable(v)
+		if !ok {
+			continue
+		}
+		tris, ok := h.triangulate(poly)
+		if !ok {
+			continue
+		}
+		marked[v] = true
+		for _, u := range poly {
+			marked[u] = true
+		}
+		h.remove(v, tris)
+		removed = true
+	}
+	return removed
+}
+
+// remove replaces the triangles around v with tris, a
+// triangulation of the polygon around v. Each new triangle
+// points to those old triangles which it overlaps.
+func (h *hierarchy) remove(v int, tris [][3]int) {
+	old := h.star(v)
+	for _, t := range old {
+		t.dead = true
+	}
+	h.stars[v] = nil
+	for _, vs := range tris {
+		tr := &Tree{}
+		t := h.add(vs, tr)
+		for _, o := range old {
+			if !h.separated(t, o) {
+				tr.children = append(tr.children, o.tree)
+			}
+		}
+	}
+}
+
+// separated reports whether a line through an edge of t1 or t2
+// has the other triangle strictly on its far side. Triangles which
+// merely touch are not separated, so that even triangles with no
+// area have children.
+func (h *hierarchy) separated(t1, t2 *triangle) bool {
+	for _, ts := range [][2]*triangle{{t1, t2}, {t2, t1}} {
+		a, b := ts[0], ts[1]
+		for i := range a.vs {
+			p := h.pts[a.vs[i]]
+			q := h.pts[a.vs[(i+1)%3]]
+			out := true
+			for _, r := range b.vs {
+				if geom.Cross2D(p, q, h.pts[r]) >= 0 {
+					out = false
+					break
+				}
+			}
+			if out {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// triangulate triangulates the counter-clockwise polygon poly
+// by clipping ears from it. The sharpest ears are clipped first,
+// and ears with no area are clipped only when the polygon is
+// straight at their tip. If a vertex of poly lies so close to
+// a chord that rounding lets an ear through which leaves poly,
+// some triangle turns clockwise, and ok is false.
+func (h *hierarchy) triangulate(poly []int) (tris [][3]int, ok bool) {
+	poly = append([]int{}, poly...)
+	for len(poly) > 3 {
+		n := len(poly)
+		best := -1
+		bestScore := math.Inf(-1)
+		fallback := 0
+		fallbackScore := math.Inf(-1)
+		for i := range poly {
+			a, b, c := poly[(i+n-1)%n], poly[i], poly[(i+1)%n]
+			s := h.turn(a, b, c)
+			if s > fallbackScore {
+				fallback, fallbackScore = i, s
+			}
+			if s > bestScore && h.isEar(poly, i) {
+				best, bestScore = i, s
+			}
+		}
+		if best == -1 {
+			best = fallback
+		}
+		a, b, c := poly[(best+n-1)%n], poly[best], poly[(best+1)%n]
+		tris = append(tris, [3]int{a, b, c})
+		poly = append(poly[:best], poly[best+1:]...)
+	}
+	tris = append(tris, [3]int{poly[0], poly[1], poly[2]})
+	for _, vs := range tris {
+		if geom.Orient2D(h.pts[vs[0]], h.pts[vs[1]], h.pts[vs[2]]) < 0 {
+			return nil, false
+		}
+	}
+	return tris, true
+}
+
+// turn returns the sine of the left turn a -> b -> c.
+func (h *hierarchy) turn(a, b, c int) float64 {
+	pa, pb, pc := h.pts[a], h.pts[b], h.pts[c]
+	l := math.Hypot(pa.X()-pb.X(), pa.Y()-pb.Y()) *
+		math.Hypot(pc.X()-pb.X(), pc.Y()-pb.Y())
+	if l == 0 {
+		return 0
+	}
+	return geom.Cross2D(pa, pb, pc) / l
+}
+
+// isEar reports whether the triangle at poly[i] can be
+// clipped from poly.
+func (h *hierarchy) isEar(poly []int, i int) bool {
+	n := len(poly)
+	a, b, c := poly[(i+n-1)%n], poly[i], poly[(i+1)%n]
+	pa, pb, pc := h.pts[a], h.pts[b], h.pts[c]
+	cp := geom.Cross2D(pa, pb, pc)
+	if cp < 0 {
+		return false
+	}
+	if cp == 0 {
+		// b must lie between a and c
+		return (pa.X()-pb.X())*(pc.X()-pb.X())+(pa.Y()-pb.Y())*(pc.Y()-pb.Y()) < 0
+	}
+	for j, v := range poly {
+		if j == i || j == (i+n-1)%n || j == (i+1)%n {
+			continue
+		}
+		p := h.pts[v]
+		if p.Eq(pa) || p.Eq(pb) || p.Eq(pc) {
+			continue
+		}
+		if geom.Cross2D(pa, pb, p) >= 0 &&
+			geom.Cross2D(pb, pc, p) >= 0 &&
+			geom.Cross2D(pc, pa, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// roots returns the triangles of the coarsest triangulation.
+func (h *hierarchy) roots() []*Tree {
+	seen := make(map[*triangle]bool)
+	roots := []*Tree{}
+	for v := range h.pts {
+		for _, t := range h.star(v) {
+			if !seen[t] {
+				seen[t] = true
+				roots = append(roots, t.tree)
+			}
+		}
+	}
+	return roots
+}
@@ -4,103 +4,125 @@
 package kirkpatrick
 
 import (
-	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/delaunay"
 	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
 	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
 	"github.com/nylen/go-compgeo/geom"
 )
 
-//Triangulation method constant
+// Triangulation method constant
 type Method int
 
 const (
 	MONOTONE Method = iota
 	TRAPEZOID
+	// DELAUNAY triangulates with a constrained Delaunay
+	// triangulation, avoiding thin triangles.
+	DELAUNAY
 )
 
-func TriangleTree(dc *dcel.DCEL, m Method) (pointLoc.LocatesPoints, error) {
+// TriangleTree triangulates dc, within a bounding box, through the given
+// method, then builds Kirkpatrick's hierarchy of coarser and coarser
+// triangulations on top of that triangulation by repeatedly removing
+// independent sets of low degree vertices. If a Tracer is given, the
+// triangulation built by the MONOTONE and TRAPEZOID methods, and the
+// triangles checked by queries on the returned locator, are traced
+// to it.
+func TriangleTree(dc *dcel.DCEL, m Method, tr ...pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+	tracer := pointLoc.OptionalTracer(tr)
 	var tri *dcel.DCEL
 	var mp map[*dcel.Face]*dcel.Face
 	var err error
 
-	// We'll remove these vertices later
-	//verts := dc.Vertices
 	switch m {
-	case MONOTONE:
-		// We need to wrap this dcel in some bounding polygon.
-		dc2 := dc.Copy()
-		// We add the bounds of dc2, expanded by 1, to create
-		// a large face out of each edge whose existing face was
-		// the outer face.
-		bounds := dc2.Bounds()
-		p1 := bounds.At(geom.SPAN_MIN)
-		p1 = p1.Set(0, p1.Val(0)-1)
-		p1 = p1.Set(1, p1.Val(1)-1)
-		p3 := bounds.At(geom.SPAN_MAX)
-		p3 = p3.Set(0, p3.Val(0)+1)
-		p3 = p3.Set(1, p3.Val(1)+1)
-		p2 := geom.NewPoint(p1.Val(0), p3.Val(1), 0)
-		p4 := geom.NewPoint(p3.Val(0), p1.Val(1), 0)
-
-		boundDc := dcel.FourPoint(p1.(geom.D3), p2, p3.(geom.D3), p4)
-
-		// Correct face pointers
-		for _, e := range dc2.HalfEdges {
-			if e.Face == dc2.Faces[dcel.OUTER_FACE] {
-				e.Face = boundDc.Faces[1]
-			}
+	case MONOTONE, DELAUNAY:
+		dc2 := boxed(dc)
+		if m == MONOTONE {
+			tri, mp, err = monotone.Triangulate(dc2, tracer)
+		} else {
+			tri, mp, err = delaunay.Constrained(dc2)
 		}
-
-		// Find a point on dc2 to connect to
-		minV := dc2.Vertices[0]
-		for _, v := range dc2.Vertices {
-			if v.X() < minV.X() {
-				minV = v
+		if err != nil {
+			return nil, err
+		}
+		// mp takes faces to dc2's faces, which share their
+		// indices with dc's faces, save for the box.
+		faceIndex := make(map[*dcel.Face]int)
+		for i, f := range dc2.Faces {
+			faceIndex[f] = i
+		}
+		for f, f2 := range mp {
+			i := faceIndex[f2]
+			if i >= len(dc.Faces) {
+				i = dcel.OUTER_FACE
 			}
+			mp[f] = dc.Faces[i]
 		}
-
-		// Combine boundDc into dc2
-		dc2.Faces[0] = boundDc.Faces[0]
-		dc2.Faces = append(dc2.Faces, boundDc.Faces[1])
-		dc2.Vertices = append(dc2.Vertices, boundDc.Vertices...)
-		dc2.HalfEdges = append(dc2.HalfEdges, boundDc.HalfEdges...)
-		v1 := dc2.Vertices[len(dc2.Vertices)-4] // Span_min
-		// add an edge from boundDc to dc2's outer edge
-		dc2.ConnectVerts(v1, minV, dc2.Faces[len(dc2.Faces)-1])
-
-		tri, mp, err = monotone.Triangulate(dc2)
 	case TRAPEZOID:
 		// The trapezoidal map method requires that we add to our
 		// dcel a wrapping square, so we already have our outer polygon.
-		tri, mp, _, err = trapezoid.TrapezoidalMap(dc)
+		tri, mp, _, err = trapezoid.TrapezoidalMap(dc, tracer)
+		if err != nil {
+			return nil, err
+		}
+		tri, mp, err = monotone.TriangulateSplit(tri, mp, tracer)
 		if err != nil {
 			return nil, err
 		}
-		tri, mp, err = monotone.TriangulateSplit(tri, mp)
-	}
-	if err != nil {
-		return nil, err
 	}
 
 	// At this point we have a base level of triangles
-	nodes := make([]*Tree, len(tri.Faces))
-	for i, t := range tri.Faces {
-		nodes[i] = &Tree{tri: t}
+	outerFace := dc.Faces[dcel.OUTER_FACE]
+	h := newHierarchy(tri)
+	for _, f := range tri.Faces[dcel.OUTER_FACE+1:] {
+		inFace, ok := mp[f]
+		if !ok || inFace == nil {
+			inFace = outerFace
+		}
+		h.addFace(f, inFace)
 	}
-
-	// Hit a wall here.
-	// We need a structure that stores every vertex and every face that
-	// each vertex borders, so when we remove a vertex we can somehow
-	// indicate which facecs are being removed and what faces are being added.
-	// this structure needs to somehow inform other vertices when a face they
-	// are next to has been removed, or it needs to be regenerated at every
-	// tier of the triangle stack.
-	return nil, compgeo.UnsupportedError{}
+	for h.reduce() {
+	}
+	return &PointLocator{h.roots(), outerFace, tracer}, nil
 }
 
-type Tree struct {
-	tri      *dcel.Face
-	children []*Tree
+// boxed returns a copy of dc wrapped in a bounding box, as the
+// last face of the copy, whose holes are dc's outer boundaries.
+func boxed(dc *dcel.DCEL) *dcel.DCEL {
+	dc2 := dc.Copy()
+	// We add the bounds of dc2, expanded by 1, to create
+	// a large face whose hole is dc2's outer boundary.
+	bounds := dc2.Bounds()
+	p1 := bounds.At(geom.SPAN_MIN)
+	p1 = p1.Set(0, p1.Val(0)-1)
+	p1 = p1.Set(1, p1.Val(1)-1)
+	p3 := bounds.At(geom.SPAN_MAX)
+	p3 = p3.Set(0, p3.Val(0)+1)
+	p3 = p3.Set(1, p3.Val(1)+1)
+	p2 := geom.NewPoint(p1.Val(0), p3.Val(1), 0)
+	p4 := geom.NewPoint(p3.Val(0), p1.Val(1), 0)
+
+	boundDc := dcel.FourPoint(p1.(geom.D3), p2, p3.(geom.D3), p4)
+	box := boundDc.Faces[1]
+
+	// Correct face pointers. Each chain of edges on the
+	// outer face becomes a hole in the box.
+	outer := dc2.Faces[dcel.OUTER_FACE]
+	for _, e := range dc2.HalfEdges {
+		if e.Face == outer {
+			box.Inner = append(box.Inner, e)
+			for _, e2 := range e.EdgeChain() {
+				e2.Face = box
+			}
+		}
+	}
+
+	// Combine boundDc into dc2
+	dc2.Faces[dcel.OUTER_FACE] = boundDc.Faces[dcel.OUTER_FACE]
+	dc2.Faces = append(dc2.Faces, box)
+	dc2.Vertices = append(dc2.Vertices, boundDc.Vertices...)
+	dc2.HalfEdges = append(dc2.HalfEdges, boundDc.HalfEdges...)
+	return dc2
 }
@@ -0,0 +1,81 @@
+package kirkpatrick
+
+import (
+	"math"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// A Tree is a triangle in a triangulation of the hierarchy.
+// Its children are the triangles of the next finer triangulation
+// which it overlaps. Triangles in the finest triangulation have
+// no children, and know which face of the input they lie in.
+type Tree struct {
+	tri      [3]geom.D2
+	face     *dcel.Face
+	children []*Tree
+}
+
+// depth returns how far inside tr p lies, as the least
+// distance from p to the line through one of tr's edges.
+// This is negative if p lies outside of tr.
+func (tr *Tree) depth(p geom.D2) float64 {
+	d := math.Inf(1)
+	for i := range tr.tri {
+		a := tr.tri[i]
+		b := tr.tri[(i+1)%3]
+		l := math.Hypot(b.X()-a.X(), b.Y()-a.Y())
+		if l == 0 {
+			continue
+		}
+		d = math.Min(d, geom.Cross2D(a, b, p)/l)
+	}
+	return d
+}
+
+// deepest returns the tree in trs which p lies furthest inside,
+// tracing each tree checked to tracer. Checking depth instead of
+// containment means rounding error cannot leave us without a tree
+// to descend into.
+func deepest(trs []*Tree, p geom.D2, tracer pointLoc.Tracer) (*Tree, float64) {
+	var best *Tree
+	bestD := math.Inf(-1)
+	for _, tr := range trs {
+		tracer.RegionChecked(tr.tri[:])
+		d := tr.depth(p)
+		if best == nil || d > bestD {
+			best = tr
+			bestD = d
+		}
+	}
+	return best, bestD
+}
+
+// PointLocator is a construct that uses Kirkpatrick's
+// triangle hierarchy for point location.
+type PointLocator struct {
+	roots     []*Tree
+	outerFace *dcel.Face
+	tracer    pointLoc.Tracer
+}
+
+// PointLocate returns which face within this PointLocator
+// the query point lands, within two dimensions. Points outside
+// of the hierarchy's bounds are located in the outer face.
+func (pl *PointLocator) PointLocate(vs ...float64) (*dcel.Face, error) {
+	if len(vs) < 2 {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	tr, d := deepest(pl.roots, p, pl.tracer)
+	if tr == nil || d < 0 {
+		return pl.outerFace, nil
+	}
+	for len(tr.children) != 0 {
+		tr, _ = deepest(tr.children, p, pl.tracer)
+	}
+	return tr.face, nil
+}
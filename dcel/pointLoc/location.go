@@ -0,0 +1,189 @@
+package pointLoc
+
+import (
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// A LocationKind says whether a point lies inside a face,
+// along an edge, or on a vertex.
+type LocationKind int
+
+// Location kinds
+const (
+	InFace LocationKind = iota
+	OnEdge
+	OnVertex
+)
+
+var locationNames = []string{"in face", "on edge", "on vertex"}
+
+func (lk LocationKind) String() string {
+	if int(lk) < len(locationNames) {
+		return locationNames[lk]
+	}
+	return "unknown location"
+}
+
+// A Location is the result of a point location query which
+// reports points on the borders between faces. Which fields
+// are set depends on Kind:
+//
+// For InFace, Face is the face containing the point, which
+// is the outer face if it is outside every other face, as
+// with PointLocate.
+//
+// For OnEdge, Edge is whichever of the two half edges along
+// the point starts at its lesser end, by x and then by y, so
+// that it points right, or up if it is vertical. Face is the
+// face of that half edge.
+//
+// For OnVertex, Vertex is the vertex at the point.
+type Location struct {
+	Kind   LocationKind
+	Face   *dcel.Face
+	Edge   *dcel.Edge
+	Vertex *dcel.Vertex
+}
+
+// ClassifiesPoints is an interface to represent point location
+// queries which tell points on vertices and edges apart from
+// points inside faces, so that every algorithm gives the same
+// answer for points on the borders between faces.
+type ClassifiesPoints interface {
+	LocatesPoints
+	Locate(x, y float64) (Location, error)
+}
+
+// Classify returns the Location of p among edges, which should
+// hold the edges nearest p, such as those directly above and below
+// it. p is on a vertex if it is at either end of one of edges, and
+// on an edge if it lies along one of them, or along a vertical edge
+// reached by following vertical edges toward p from an end of one
+// of them. Otherwise p lies inside the face in.
+func Classify(p geom.D2, in *dcel.Face, edges ...*dcel.Edge) Location {
+	var on *dcel.Edge
+	for _, e := range edges {
+		if e == nil {
+			continue
+		}
+		for _, v := range []*dcel.Vertex{e.Origin, e.Twin.Origin} {
+			if v.X() == p.X() && v.Y() == p.Y() {
+				return Location{Kind: OnVertex, Vertex: v}
+			}
+		}
+		if on == nil && geom.Orient2D(e.Origin, e.Twin.Origin, p) == 0 &&
+			between(e.Origin, p, e.Twin.Origin) {
+			on = e
+		}
+	}
+	if on == nil {
+		for _, e := range edges {
+			if e == nil {
+				continue
+			}
+			loc, ok := vertical(p, e.Origin)
+			if !ok {
+				loc, ok = vertical(p, e.Twin.Origin)
+			}
+			if ok {
+				return loc
+			}
+		}
+		return Location{Kind: InFace, Face: in}
+	}
+	// Edges along p may be found before a vertex at p,
+	// so only once every edge is checked is p on an edge.
+	return onEdge(on)
+}
+
+// vertical follows the vertical edges from v toward p,
+// which shares v's x value, and reports where p lies
+// if it is on one of them.
+func vertical(p geom.D2, v *dcel.Vertex) (Location, bool) {
+	if v.X() != p.X() {
+		return Location{}, false
+	}
+	for {
+		var next *dcel.Vertex
+		for _, e := range v.AllEdges() {
+			w := e.Twin.Origin
+			if w.X() != p.X() || (w.Y() > v.Y()) != (p.Y() > v.Y()) {
+				continue
+			}
+			if w.Y() == p.Y() {
+				return Location{Kind: OnVertex, Vertex: w}, true
+			}
+			if between(v, p, w) {
+				return onEdge(e), true
+			}
+			next = w
+		}
+		if next == nil {
+			return Location{}, false
+		}
+		v = next
+	}
+}
+
+// onEdge returns the Location of a point along e.
+func onEdge(e *dcel.Edge) Location {
+	if lexLess(e.Twin.Origin, e.Origin) {
+		e = e.Twin
+	}
+	return Location{Kind: OnEdge, Face: e.Face, Edge: e}
+}
+
+// Touching returns a face whose boundary holds the point of l, other
+// than outer if there is one, or if l is InFace, the face of l. Point
+// locators give this face for points on vertices and edges.
+func (l Location) Touching(outer *dcel.Face) *dcel.Face {
+	edges := []*dcel.Edge{}
+	switch l.Kind {
+	case InFace:
+		return l.Face
+	case OnEdge:
+		edges = append(edges, l.Edge)
+	case OnVertex:
+		edges = l.Vertex.AllEdges()
+	}
+	for _, e := range edges {
+		for _, f := range []*dcel.Face{e.Face, e.Twin.Face} {
+			if f != outer {
+				return f
+			}
+		}
+	}
+	return outer
+}
+
+// FaceEdges returns the edges around each boundary of faces,
+// for locators which classify points against whole faces.
+func FaceEdges(faces ...*dcel.Face) []*dcel.Edge {
+	edges := []*dcel.Edge{}
+	for _, f := range faces {
+		if f == nil {
+			continue
+		}
+		for _, start := range f.Boundaries() {
+			edges = append(edges, start.EdgeChain()...)
+		}
+	}
+	return edges
+}
+
+// between reports whether b lies strictly between a and c,
+// which lie on one line with it.
+func between(a, b, c geom.D2) bool {
+	if lexLess(c, a) {
+		a, c = c, a
+	}
+	return lexLess(a, b) && lexLess(b, c)
+}
+
+func lexLess(a, b geom.D2) bool {
+	if a.X() != b.X() {
+		return a.X() < b.X()
+	}
+	return a.Y() < b.Y()
+}
@@ -1,8 +1,7 @@
 package monotone
 
 import (
-	"github.com/nylen/go-compgeo/dcel"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/search"
 )
@@ -12,63 +11,102 @@ import (
 // tree and compared horizontally
 //
 // See also: slab/compEdge.go
-// these structures, or at least the Compare function portion
-// should probably be in dcel
+//
+// The edges stored are those running from a corner to its
+// next corner downward, which have their face on the right.
 
 type edgeNode struct {
-	v *dcel.Edge
+	c      *corner
+	tracer pointLoc.Tracer
 }
 
 func (en edgeNode) Key() search.Comparable {
-	return compEdge{en.v}
+	return compEdge{en.c, en.tracer}
 }
 
 func (en edgeNode) Val() search.Equalable {
-	return valEdge{en.v}
+	return valEdge{en.c}
 }
 
 type valEdge struct {
-	*dcel.Edge
+	*corner
 }
 
 func (ve valEdge) Equals(e search.Equalable) bool {
 	switch ve2 := e.(type) {
 	case valEdge:
-		return ve.Edge == ve2.Edge
+		return ve.corner == ve2.corner
 	}
 	return false
 }
 
 // We need to have our keys be CompEdges so
 // they are comparable within a certain y range.
+//
+// Two edges in the sweep status never cross, so
+// whichever edge begins lower in the sweep has its
+// upper point to the left or right of the other edge,
+// and that decides their order for as long as both
+// are in the tree. Each comparison between two edges
+// is traced to tracer.
 type compEdge struct {
-	*dcel.Edge
+	*corner
+	tracer pointLoc.Tracer
+}
+
+// side returns how this edge compares to p, Less if
+// the edge is to the left of p.
+func (ce compEdge) side(p geom.D2) search.CompareResult {
+	cp := geom.Orient2D(ce.corner, ce.next, p)
+	if cp > 0 {
+		return search.Less
+	} else if cp < 0 {
+		return search.Greater
+	}
+	return search.Equal
 }
 
 func (ce compEdge) Compare(i interface{}) search.CompareResult {
 	switch c := i.(type) {
 	case compEdge:
-		if visualize.VisualCh != nil {
-			visualize.DrawLine(ce.Edge.Origin, ce.Edge.Twin.Origin)
-			visualize.DrawLine(c.Edge.Origin, c.Edge.Twin.Origin)
-		}
-		if ce.Edge == c.Edge {
+		ce.tracer.EdgeChecked(ce.corner, ce.next)
+		ce.tracer.EdgeChecked(c.corner, c.next)
+		if ce.corner == c.corner {
 			return search.Equal
 		}
-
-		if geom.F64eq(ce.X(), c.X()) && geom.F64eq(ce.Y(), c.Y()) &&
-			geom.F64eq(ce.Twin.X(), c.Twin.X()) && geom.F64eq(ce.Twin.Y(), c.Twin.Y()) {
-			return search.Equal
+		if above(ce.corner, c.corner) {
+			if r := ce.side(c.corner); r != search.Equal {
+				return r
+			}
+			if r := ce.side(c.next); r != search.Equal {
+				return r
+			}
+		} else {
+			if r := c.side(ce.corner); r != search.Equal {
+				return flip(r)
+			}
+			if r := c.side(ce.next); r != search.Equal {
+				return flip(r)
+			}
 		}
-		y, err := ce.FindSharedPoint(c.Edge, 1)
-		if err != nil {
-		}
-		p1, _ := ce.PointAt(1, y)
-		p2, _ := c.PointAt(1, y)
-		if p1[0] < p2[0] {
+		// Colinear, overlapping edges. These do not exist in
+		// valid input, but we still need a consistent order.
+		if above(ce.next, c.next) {
 			return search.Less
 		}
 		return search.Greater
+	case geom.D2:
+		return ce.side(c)
+	}
+	return search.Invalid
+}
+
+func flip(r search.CompareResult) search.CompareResult {
+	switch r {
+	case search.Less:
+		return search.Greater
+	case search.Greater:
+		return search.Less
 	}
-	return ce.Edge.Compare(i)
+	return r
 }
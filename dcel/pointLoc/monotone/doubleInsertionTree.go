@@ -16,27 +16,35 @@ import (
 // make an interval tree, we just make bsts. The intervals
 // we use will be non-overlapping except at vertices
 func NewDoubleIntervalTree(f *dcel.Face, dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
-	var e *dcel.Edge
-	for e = f.Outer; VertexType(e.Origin, dc) != START; e = e.Next {
+	st, end := f.Outer, f.Outer
+	for _, e := range f.Outer.EdgeChain() {
+		if above(e.Origin, st.Origin) {
+			st = e
+		}
+		if above(end.Origin, e.Origin) {
+			end = e
+		}
 	}
-	// e is now the edge whose origin is the start vertex.
+	// st is now the edge whose origin is the start vertex.
 	leftTree := tree.New(tree.RedBlack)
 	rightTree := tree.New(tree.RedBlack)
-	st := e
 	leftTree.Insert(interval{st})
 	tree := leftTree
 	for e := st.Next; e != st; e = e.Next {
-		if VertexType(e.Origin, dc) == END {
+		if e == end {
 			tree = rightTree
 		}
 		tree.Insert(interval{e})
 	}
-	return DblIntervalTree{leftTree, rightTree, f}, nil
+	return DblIntervalTree{leftTree, rightTree, f, dc.Faces[dcel.OUTER_FACE]}, nil
 }
 
+// A DblIntervalTree locates points in the subdivision made of
+// its one face, f, and the outer face of f's dcel around it.
 type DblIntervalTree struct {
 	leftTree, rightTree search.Dynamic
 	f                   *dcel.Face
+	outerFace           *dcel.Face
 }
 
 func (dit DblIntervalTree) PointLocate(vs ...float64) (*dcel.Face, error) {
@@ -45,11 +53,11 @@ func (dit DblIntervalTree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	}
 	found, i1 := dit.leftTree.Search(yVal(vs[1]))
 	if !found {
-		return nil, nil
+		return dit.outerFace, nil
 	}
 	found2, i2 := dit.rightTree.Search(yVal(vs[1]))
 	if !found2 {
-		return nil, nil
+		return dit.outerFace, nil
 	}
 	e1 := i1.(interval).Edge
 	e2 := i2.(interval).Edge
@@ -70,7 +78,7 @@ func (dit DblIntervalTree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	} else if c1*c2 < 0 {
 		return dit.f, nil
 	}
-	return nil, nil
+	return dit.outerFace, nil
 }
 
 type interval struct {
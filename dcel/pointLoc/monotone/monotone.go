@@ -4,20 +4,28 @@ package monotone
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/search"
 	"github.com/nylen/go-compgeo/search/tree"
 )
 
-type helper struct {
-	*dcel.Vertex
-	typ int
-}
-
 // Split converts a dcel into another dcel of y
 // monotone shapes, along with a mapping of faces in the new set
 // to faces in the input set.
-func Split(inDc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+// If a Tracer is given, the sweep over each face and the diagonals
+// added to split it are traced to it. The sweep runs from top to
+// bottom, so its sweep lines are horizontal.
+func Split(inDc *dcel.DCEL, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	return SplitWith(inDc, tree.RedBlack, tr...)
+}
+
+// SplitWith acts as Split, using the given type of
+// BST to hold the edges crossing its sweep line.
+func SplitWith(inDc *dcel.DCEL, bstType tree.Type, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	tracer := pointLoc.OptionalTracer(tr)
 
 	dc := inDc.Copy()
 
@@ -30,98 +38,183 @@ func Split(inDc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
 	// dc.Faces is modified through this algorithm,
 	// so we need to iterate it's current length (ignoring OUTER_FACE)
 	faceLen := len(dc.Faces)
-	edgeTree := tree.New(tree.RedBlack)
-	edgeLen := len(dc.HalfEdges)
 
 	for i := dcel.OUTER_FACE + 1; i < faceLen; i++ {
 		f := dc.Faces[i]
-		ypts := f.VerticesSorted(1, 0)
-		helpers := make(map[*dcel.Edge]helper)
-		for _, v := range ypts {
-			switch VertexType(v, dc) {
-			case START:
-				// Insert v's edge, map that edge to v
-				e := CounterClockwiseEdge(v, dc)
-				edgeTree.Insert(edgeNode{e})
-				helpers[e] = helper{v, START}
-			case END:
-				e := CounterClockwiseEdge(v, dc).Prev
-				// If the previous edge's helper is a merge vertex,
-				// we want to insert a diagonal in the dcel from v
-				// to that helper.
-				err := MergeInsert(helpers, f, e, v, dc)
-				if err != nil {
-					return nil, nil, err
-				}
-				delete(helpers, e)
-			case REGULAR:
-				if IsLeftOf(v, f, dc) {
-					e := CounterClockwiseEdge(v, dc)
-					prev := e.Prev
-					err := MergeInsert(helpers, f, prev, v, dc)
-					if err != nil {
-						return nil, nil, err
-					}
-					delete(helpers, prev)
-					edgeTree.Insert(edgeNode{e})
-					helpers[e] = helper{v, REGULAR}
-				} else {
-					e2 := CounterClockwiseEdge(v, dc)
-					c, _ := edgeTree.SearchDown(compEdge{e2}, 1)
-					e := c.(compEdge).Edge
-					err := MergeInsert(helpers, f, e, v, dc)
-					if err != nil {
-						return nil, nil, err
-					}
-					helpers[e] = helper{v, REGULAR}
-				}
-			case MERGE:
-				e2 := CounterClockwiseEdge(v, dc)
-				e := e2.Prev
-				err := MergeInsert(helpers, f, e, v, dc)
+		if f.Outer == nil || degenerate(f.Outer) {
+			continue
+		}
+		diagonals, err := splitDiagonals(faceCorners(f), bstType, tracer)
+		if err != nil {
+			return nil, nil, err
+		}
+		edgeLen := len(dc.HalfEdges)
+		for _, d := range diagonals {
+			dc.ConnectVerts(d[0], d[1], f)
+		}
+		if len(diagonals) != 0 {
+			splitFace(dc, f, edgeLen, faceMap)
+		}
+	}
+	return dc, faceMap, nil
+}
+
+// splitDiagonals runs the plane sweep for a single face, returning
+// the diagonals which need to be added to split it into monotone pieces.
+func splitDiagonals(corners []*corner, bstType tree.Type, tracer pointLoc.Tracer) ([][2]*dcel.Vertex, error) {
+	sort.Slice(corners, func(i, j int) bool {
+		return above(corners[i], corners[j])
+	})
+	edgeTree := tree.New(bstType)
+	// helpers are keyed by the upper corner of each edge in edgeTree
+	helpers := make(map[*corner]*corner)
+	diagonals := [][2]*dcel.Vertex{}
+
+	connect := func(a, b *dcel.Vertex) {
+		tracer.EdgeAdded(a, b)
+		diagonals = append(diagonals, [2]*dcel.Vertex{a, b})
+	}
+	// insert and remove add and remove the edge starting
+	// at c to and from edgeTree.
+	insert := func(c *corner) {
+		tracer.EdgeAdded(c.Vertex, c.next.Vertex)
+		edgeTree.Insert(edgeNode{c, tracer})
+	}
+	remove := func(c *corner) {
+		tracer.EdgeRemoved(c.Vertex, c.next.Vertex)
+		edgeTree.Delete(edgeNode{c, tracer})
+	}
+	// mergeInsert adds a diagonal from v to the helper of the
+	// edge starting at c, if that helper is a merge vertex.
+	mergeInsert := func(c, v *corner) error {
+		help, ok := helpers[c]
+		if !ok {
+			return errors.New("Malformed helpers")
+		}
+		if help.typ == MERGE {
+			connect(help.Vertex, v.Vertex)
+		}
+		return nil
+	}
+	// leftOf returns the edge directly left of v in edgeTree.
+	leftOf := func(v *corner) (*corner, error) {
+		k, _ := edgeTree.SearchDown(v.Vertex, 0)
+		if k == nil {
+			return nil, errors.New("No edge left of vertex")
+		}
+		ce := k.(compEdge)
+		if ce.side(v.Vertex) == search.Greater {
+			return nil, errors.New("No edge left of vertex")
+		}
+		return ce.corner, nil
+	}
+
+	for _, v := range corners {
+		tracer.SweepLine(v)
+		switch v.typ {
+		case START:
+			insert(v)
+			helpers[v] = v
+		case END:
+			err := mergeInsert(v.prev, v)
+			if err != nil {
+				return nil, err
+			}
+			remove(v.prev)
+			delete(helpers, v.prev)
+		case SPLIT:
+			e, err := leftOf(v)
+			if err != nil {
+				return nil, err
+			}
+			connect(helpers[e].Vertex, v.Vertex)
+			helpers[e] = v
+			insert(v)
+			helpers[v] = v
+		case MERGE:
+			err := mergeInsert(v.prev, v)
+			if err != nil {
+				return nil, err
+			}
+			remove(v.prev)
+			delete(helpers, v.prev)
+			e, err := leftOf(v)
+			if err != nil {
+				return nil, err
+			}
+			err = mergeInsert(e, v)
+			if err != nil {
+				return nil, err
+			}
+			helpers[e] = v
+		case REGULAR:
+			// If the face lies to the right of v
+			if above(v.prev, v) {
+				err := mergeInsert(v.prev, v)
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
-				delete(helpers, e)
-				c, _ := edgeTree.SearchDown(compEdge{e2}, 1)
-				e = c.(compEdge).Edge
-				err = MergeInsert(helpers, f, e, v, dc)
+				remove(v.prev)
+				delete(helpers, v.prev)
+				insert(v)
+				helpers[v] = v
+			} else {
+				e, err := leftOf(v)
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
-				helpers[e] = helper{v, MERGE}
-
-			case SPLIT:
-				e2 := CounterClockwiseEdge(v, dc)
-				c, _ := edgeTree.SearchDown(compEdge{e2}, 1)
-				e := c.(compEdge).Edge
-				err := MergeInsert(helpers, f, e, v, dc)
+				err = mergeInsert(e, v)
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
-				edgeTree.Insert(edgeNode{e2})
-				helpers[e] = helper{v, SPLIT}
-				helpers[e2] = helper{v, SPLIT}
+				helpers[e] = v
 			}
 		}
-		// Walk each new edge to create new faces.
-		for i := edgeLen; i < len(dc.HalfEdges); i++ {
-			e := dc.HalfEdges[i]
-			if e.Face == f {
-				newFace := dcel.NewFace()
-				newFace.Outer = e
-				e.Face = newFace
-				for e = e.Next; e != newFace.Outer; e = e.Next {
-					e.Face = newFace
-				}
-				faceMap[newFace] = f
-			} // else we've already walked this edge
+	}
+	return diagonals, nil
+}
+
+// splitFace walks the chains of f after diagonals have been added
+// to it, starting at dc.HalfEdges[edgeLen]. The chain holding f.Outer
+// keeps f, and each other chain is given a new face.
+func splitFace(dc *dcel.DCEL, f *dcel.Face, edgeLen int, faceMap map[*dcel.Face]*dcel.Face) {
+	orig, ok := faceMap[f]
+	if !ok {
+		orig = f
+		faceMap[f] = f
+	}
+	starts := f.Boundaries()
+	starts = append(starts, dc.HalfEdges[edgeLen:]...)
+	seen := make(map[*dcel.Edge]bool)
+	f.Inner = nil
+	for i, e := range starts {
+		if e == nil || seen[e] {
+			continue
+		}
+		face := f
+		if i != 0 {
+			face = dcel.NewFace()
+			dc.Faces = append(dc.Faces, face)
+			faceMap[face] = orig
+		}
+		face.Outer = e
+		for _, e2 := range e.EdgeChain() {
+			e2.Face = face
+			seen[e2] = true
 		}
-		edgeLen = len(dc.HalfEdges)
 	}
-	return nil, nil, nil
 }
 
+type helper struct {
+	*dcel.Vertex
+	typ int
+}
+
+// CounterClockwiseEdge returns the edge leaving v which runs
+// counter-clockwise around its face.
+//
+// Deprecated: Split no longer uses this, and it only holds for vertices
+// with a single edge on each side. It will be removed in a future release.
 func CounterClockwiseEdge(v *dcel.Vertex, dc *dcel.DCEL) *dcel.Edge {
 	if v.OutEdge.Face == dc.Faces[dcel.OUTER_FACE] {
 		return v.OutEdge
@@ -129,6 +222,11 @@ func CounterClockwiseEdge(v *dcel.Vertex, dc *dcel.DCEL) *dcel.Edge {
 	return v.OutEdge.Twin.Prev
 }
 
+// MergeInsert connects v to the helper of e in f, if that
+// helper is a merge vertex.
+//
+// Deprecated: Split keeps its helpers internally and no longer uses
+// this. It will be removed in a future release.
 func MergeInsert(helpers map[*dcel.Edge]helper, f *dcel.Face, e *dcel.Edge,
 	v *dcel.Vertex, dc *dcel.DCEL) error {
 	if help, ok := helpers[e]; ok {
@@ -142,6 +240,9 @@ func MergeInsert(helpers map[*dcel.Edge]helper, f *dcel.Face, e *dcel.Edge,
 
 // IsLeftOf returns whether v is to the left or to the right
 // of --the face which neighbors v--, f
+//
+// Deprecated: Split no longer uses this, and it only holds for vertices
+// with a single edge on each side. It will be removed in a future release.
 func IsLeftOf(v *dcel.Vertex, f *dcel.Face, dc *dcel.DCEL) bool {
 	// All internal faces have their points oriented counter-clockwise
 	// We know that one of v's previous or next points is above,
@@ -2,80 +2,129 @@ package monotone
 
 import (
 	"errors"
+	"math"
+	"sort"
 
 	"github.com/nylen/go-compgeo/dcel"
 	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/geom"
 )
 
 // Triangulate uses Monotonization to convert a dcel into
-// a dcel made up of just triangles
-func Triangulate(inDc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
-	monotonized, faceMap, err := Split(inDc)
+// a dcel made up of just triangles. If a Tracer is given,
+// both steps are traced to it, as in Split and TriangulateSplit.
+func Triangulate(inDc *dcel.DCEL, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	monotonized, faceMap, err := Split(inDc, tr...)
 	if err != nil {
 		return monotonized, faceMap, err
 	}
-	return TriangulateSplit(monotonized, faceMap)
+	return TriangulateSplit(monotonized, faceMap, tr...)
 }
 
 // TriangulateSplit takes in a dcel whose faces are already
 // monotone. If there is no existing faceMap, it will
-// create its own.
-func TriangulateSplit(monotonized *dcel.DCEL, faceMap map[*dcel.Face]*dcel.Face) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
-	edgeLen := len(monotonized.HalfEdges)
+// create its own. If a Tracer is given, the sweep over each
+// face and the diagonals added to triangulate it are traced
+// to it.
+func TriangulateSplit(monotonized *dcel.DCEL, faceMap map[*dcel.Face]*dcel.Face, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, error) {
+	tracer := pointLoc.OptionalTracer(tr)
 	if faceMap == nil {
 		faceMap = make(map[*dcel.Face]*dcel.Face)
 	}
 	// Triangulate each monotone polygon
-	for _, f := range monotonized.Faces {
-		ypts := f.VerticesSorted(1, 0)
-		chainMap, err := Chains(monotonized, f, ypts)
-		if err != nil {
-			return monotonized, faceMap, err
+	faceLen := len(monotonized.Faces)
+	for i := dcel.OUTER_FACE + 1; i < faceLen; i++ {
+		f := monotonized.Faces[i]
+		if f.Outer == nil || len(f.Inner) != 0 {
+			return monotonized, faceMap, errors.New("A face on the input DCEL was not monotone")
 		}
-		plTree, err := NewDoubleIntervalTree(f, monotonized)
+		corners := chainCorners(f.Outer, true)
+		if len(corners) <= 3 || degenerate(f.Outer) {
+			continue
+		}
+		diagonals, err := triangulateDiagonals(corners, tracer)
 		if err != nil {
 			return monotonized, faceMap, err
 		}
-		stack := VertexStack{}
-		stack.Push(ypts[0], ypts[1])
-		var v *dcel.Vertex
-		for i := 2; i < len(ypts)-1; i++ {
-			if chainMap[ypts[i]] != chainMap[stack.first.Vertex] {
-				v = stack.Pop()
-				for !stack.IsEmpty() {
-					monotonized.ConnectVerts(v, ypts[i], f)
-					v = stack.Pop()
+		edgeLen := len(monotonized.HalfEdges)
+		for _, d := range diagonals {
+			monotonized.ConnectVerts(d[0], d[1], f)
+		}
+		if len(diagonals) != 0 {
+			splitFace(monotonized, f, edgeLen, faceMap)
+		}
+	}
+	return monotonized, faceMap, nil
+}
+
+// triangulateDiagonals returns the diagonals which triangulate
+// the y-monotone polygon made up of corners.
+func triangulateDiagonals(corners []*corner, tracer pointLoc.Tracer) ([][2]*dcel.Vertex, error) {
+	chainMap, err := chains(corners)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(corners, func(i, j int) bool {
+		return above(corners[i], corners[j])
+	})
+	diagonals := [][2]*dcel.Vertex{}
+	connect := func(a, b *dcel.Vertex) {
+		tracer.EdgeAdded(a, b)
+		diagonals = append(diagonals, [2]*dcel.Vertex{a, b})
+	}
+	tracer.SweepLine(corners[0])
+	tracer.SweepLine(corners[1])
+	stack := VertexStack{}
+	stack.Push(corners[0].Vertex, corners[1].Vertex)
+	for i := 2; i < len(corners)-1; i++ {
+		tracer.SweepLine(corners[i])
+		u := corners[i].Vertex
+		if chainMap[u] != chainMap[stack.Peek()] {
+			// Connect u to everything on the stack, save
+			// the bottom of the stack which u already neighbors.
+			for !stack.IsEmpty() {
+				v := stack.Pop()
+				if !stack.IsEmpty() {
+					connect(u, v)
 				}
-				stack.Push(ypts[i-1], ypts[i])
-			} else {
-				stack.Pop()
-				for {
-					v = stack.Pop()
-					if DiagonalWithinFace(plTree, v, ypts[i]) {
-						monotonized.ConnectVerts(v, ypts[i], f)
-					} else {
-						break
-					}
+			}
+			stack.Push(corners[i-1].Vertex, u)
+		} else {
+			last := stack.Pop()
+			for !stack.IsEmpty() {
+				v := stack.Peek()
+				// The diagonal from u to v is in the face if
+				// last is convex.
+				if chainMap[u] == aChain && !convex(v, last, u) ||
+					chainMap[u] == bChain && !convex(u, last, v) {
+					break
 				}
-				stack.Push(v, ypts[i])
+				last = stack.Pop()
+				connect(u, last)
 			}
+			stack.Push(last, u)
 		}
-		// Walk each new edge to create new faces.
-		for i := edgeLen; i < len(monotonized.HalfEdges); i++ {
-			e := monotonized.HalfEdges[i]
-			if e.Face == f {
-				newFace := dcel.NewFace()
-				newFace.Outer = e
-				e.Face = newFace
-				for e = e.Next; e != newFace.Outer; e = e.Next {
-					e.Face = newFace
-				}
-				faceMap[newFace] = f
-			} // else we've already walked this edge
+	}
+	tracer.SweepLine(corners[len(corners)-1])
+	u := corners[len(corners)-1].Vertex
+	stack.Pop()
+	for !stack.IsEmpty() {
+		v := stack.Pop()
+		if !stack.IsEmpty() {
+			connect(u, v)
 		}
-		edgeLen = len(monotonized.HalfEdges)
 	}
-	return monotonized, faceMap, nil
+	return diagonals, nil
+}
+
+// convex reports whether a -> b -> c turns left. Turns
+// close enough to straight that their sign is just rounding
+// error are not convex, so we do not add diagonals which lie
+// along the face's boundary.
+func convex(a, b, c geom.D2) bool {
+	ab := math.Hypot(a.X()-b.X(), a.Y()-b.Y())
+	cb := math.Hypot(c.X()-b.X(), c.Y()-b.Y())
+	return geom.Cross2D(a, b, c) > 1e-10*ab*cb
 }
 
 type chain bool
@@ -85,85 +134,100 @@ const (
 	bChain chain = false
 )
 
-func Chains(dc *dcel.DCEL, f *dcel.Face, pts []*dcel.Vertex) (map[*dcel.Vertex]chain, error) {
-	// find the single start and end vertices
-	var start, end *dcel.Vertex
+// chains splits the corners of a y-monotone polygon into
+// its left chain, aChain, which includes its top vertex, and its
+// right chain, bChain, which includes its bottom vertex.
+// The corners must be ordered counter-clockwise.
+func chains(corners []*corner) (map[*dcel.Vertex]chain, error) {
 	m := make(map[*dcel.Vertex]chain)
-	for _, p := range pts {
-		typ := VertexType(p, dc)
-		if typ == START {
-			if start != nil {
-				return m, errors.New("A face on the input DCEL was not monotone")
-			}
-			start = p
-		} else if typ == END {
-			if end != nil {
-				return m, errors.New("A face on the input DCEL was not monotone")
-			}
-			end = p
+	if len(corners) == 0 {
+		return m, nil
+	}
+	top := corners[0]
+	bottom := corners[0]
+	for _, c := range corners {
+		if above(c, top) {
+			top = c
+		}
+		if above(bottom, c) {
+			bottom = c
 		}
 	}
-
-	e := f.Outer
-	for {
-		if e.Origin == start {
-			break
+	c := top
+	for ; c != bottom; c = c.next {
+		if above(c.next, c) {
+			return m, errors.New("A face on the input DCEL was not monotone")
 		}
-		e = e.Next
+		m[c.Vertex] = aChain
 	}
-
-	// We'll say that start falls into the chain a.
-	for e.Origin != end {
-		m[e.Origin] = aChain
-		// We don't care about the directionaliy
-		// of the face, we just need to distinguish
-		// the left and right chains. Whichever of
-		// left and right ends up as chain A is
-		// insignificant.
-		e = e.Next
+	for ; c != top; c = c.next {
+		if above(c, c.next) {
+			return m, errors.New("A face on the input DCEL was not monotone")
+		}
+		m[c.Vertex] = bChain
 	}
-
 	return m, nil
 }
 
-func DiagonalWithinFace(tree pointLoc.LocatesPoints, a, b *dcel.Vertex) bool {
-	mid := a.Mid2D(b)
-	f, _ := tree.PointLocate(mid.X(), mid.Y())
-	return f != nil
-}
-
-type VertexStackItem struct {
-	*dcel.Vertex
-	next, prev *VertexStackItem
-}
-
+// A VertexStack is a LIFO stack of vertices.
 type VertexStack struct {
-	first, last *VertexStackItem
+	vs []*dcel.Vertex
 }
 
+// IsEmpty returns whether the stack has no vertices.
 func (vst *VertexStack) IsEmpty() bool {
-	return vst.first == nil
+	return len(vst.vs) == 0
 }
 
+// Push adds vs to the stack, in order.
 func (vst *VertexStack) Push(vs ...*dcel.Vertex) {
-	for _, v := range vs {
-		item := &VertexStackItem{Vertex: v}
-		if vst.last == nil {
-			vst.first = item
-			vst.last = item
-			return
-		}
-		vst.last.next = item
-		item.prev = vst.last
-		vst.last = item
+	vst.vs = append(vst.vs, vs...)
+}
+
+// Peek returns the most recently pushed vertex without
+// removing it.
+func (vst *VertexStack) Peek() *dcel.Vertex {
+	if len(vst.vs) == 0 {
+		return nil
 	}
+	return vst.vs[len(vst.vs)-1]
 }
 
+// Pop removes and returns the most recently pushed vertex.
 func (vst *VertexStack) Pop() *dcel.Vertex {
-	if vst.first == nil {
-		return nil
+	v := vst.Peek()
+	if v != nil {
+		vst.vs = vst.vs[:len(vst.vs)-1]
 	}
-	v := vst.first.Vertex
-	vst.first = vst.first.next
 	return v
 }
+
+// Chains splits the vertices of the y-monotone face f into its
+// left chain, aChain, and its right chain, bChain. pts is unused.
+//
+// Deprecated: TriangulateSplit finds the chains of each face itself.
+// It will be removed in a future release.
+func Chains(dc *dcel.DCEL, f *dcel.Face, pts []*dcel.Vertex) (map[*dcel.Vertex]chain, error) {
+	return chains(chainCorners(f.Outer, true))
+}
+
+// DiagonalWithinFace returns whether the midpoint of a and b lies
+// in some bounded face of tree, rather than its outer face.
+//
+// Deprecated: TriangulateSplit checks its diagonals by the turns of the
+// face's boundary, and no longer uses this. It will be removed in a
+// future release.
+func DiagonalWithinFace(tree pointLoc.LocatesPoints, a, b *dcel.Vertex) bool {
+	mid := a.Mid2D(b)
+	f, _ := tree.PointLocate(mid.X(), mid.Y())
+	return f != nil && f.Outer != nil
+}
+
+// A VertexStackItem was an entry in a VertexStack.
+//
+// Deprecated: VertexStack now holds its vertices in a slice. This
+// will be removed in a future release.
+type VertexStackItem struct {
+	*dcel.Vertex
+	next, prev *VertexStackItem
+}
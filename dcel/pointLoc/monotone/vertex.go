@@ -1,6 +1,8 @@
 package monotone
 
 import (
+	"math"
+
 	"github.com/nylen/go-compgeo/dcel"
 	"github.com/nylen/go-compgeo/geom"
 )
@@ -14,28 +16,106 @@ const (
 	MERGE
 )
 
-func VertexType(v *dcel.Vertex, dc *dcel.DCEL) int {
-	p := v.OutEdge.Prev.Origin
-	n := v.OutEdge.Next.Origin
-
-	cp := geom.Cross2D(p, v, n)
-	if v.OutEdge.Face == dc.Faces[dcel.OUTER_FACE] {
-		cp *= -1
-	}
-	if geom.Lesser2D(p, v) == p {
-		if geom.Lesser2D(n, v) == n {
-			// This might need to be flipped.
-			if cp < 0 {
-				return START
-			}
-			return SPLIT
+// VertexType classifies v, whose neighbors on a face boundary are
+// p and n, assuming the boundary is oriented so that p -> v -> n
+// has the face on its left. Points are ordered first by y, and
+// then by lesser x, so horizontal edges are handled as if they
+// were tilted slightly downward to the right.
+func VertexType(p, v, n geom.D2) int {
+	cp := geom.Orient2D(p, v, n)
+	if above(v, p) && above(v, n) {
+		if cp > 0 {
+			return START
 		}
-	} else if geom.Greater2D(n, v) == n {
-		if cp < 0 {
+		return SPLIT
+	}
+	if above(p, v) && above(n, v) {
+		if cp > 0 {
 			return END
 		}
 		return MERGE
 	}
 	return REGULAR
+}
+
+// above reports whether a comes before b in a top to
+// bottom sweep.
+func above(a, b geom.D2) bool {
+	if a.Y() != b.Y() {
+		return a.Y() > b.Y()
+	}
+	return a.X() < b.X()
+}
+
+// A corner is an appearance of a vertex on a face's boundary,
+// linked to its neighbors such that the face is on the left of
+// prev -> corner -> next.
+type corner struct {
+	*dcel.Vertex
+	prev, next *corner
+	typ        int
+}
+
+// faceCorners returns the corners of all of the boundary chains
+// of f, oriented such that f is always on their left. The outer
+// chain is oriented counter-clockwise and the inner chains clockwise.
+func faceCorners(f *dcel.Face) []*corner {
+	corners := []*corner{}
+	if f.Outer != nil {
+		corners = append(corners, chainCorners(f.Outer, true)...)
+	}
+	for _, e := range f.Inner {
+		corners = append(corners, chainCorners(e, false)...)
+	}
+	return corners
+}
 
+// degenerate reports whether the chain starting at e has
+// no meaningful area, as is the case for faces whose
+// vertices all lie along a single line.
+func degenerate(e *dcel.Edge) bool {
+	area := chainArea(e)
+	sp := geom.NewSpan()
+	for _, e2 := range e.EdgeChain() {
+		sp = sp.Expand(e2.Origin)
+	}
+	d := sp.Diff()
+	w, h := d.X(), d.Y()
+	return math.Abs(area) <= 1e-9*(w*w+h*h)
+}
+
+func chainCorners(e *dcel.Edge, ccw bool) []*corner {
+	chain := e.EdgeChain()
+	cs := make([]*corner, len(chain))
+	for i, e2 := range chain {
+		cs[i] = &corner{Vertex: e2.Origin}
+	}
+	if (chainArea(e) > 0) != ccw {
+		for i, j := 0, len(cs)-1; i < j; i, j = i+1, j-1 {
+			cs[i], cs[j] = cs[j], cs[i]
+		}
+	}
+	for i, c := range cs {
+		c.next = cs[(i+1)%len(cs)]
+		c.prev = cs[(i+len(cs)-1)%len(cs)]
+	}
+	for _, c := range cs {
+		c.typ = VertexType(c.prev, c, c.next)
+	}
+	return cs
+}
+
+// chainArea returns the signed area of the chain starting at e,
+// positive if the chain is counter-clockwise. Points are taken
+// relative to e's origin to limit rounding error on small faces
+// far from the origin.
+func chainArea(e *dcel.Edge) float64 {
+	area := 0.0
+	o := e.Origin
+	for _, e2 := range e.EdgeChain() {
+		a := e2.Origin
+		b := e2.Next.Origin
+		area += (a.X()-o.X())*(b.Y()-o.Y()) - (b.X()-o.X())*(a.Y()-o.Y())
+	}
+	return area / 2
 }
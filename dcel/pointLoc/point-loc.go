@@ -2,10 +2,92 @@
 
 package pointLoc
 
-import "github.com/nylen/go-compgeo/dcel"
+import (
+	"runtime"
+	"sync"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
 
 // LocatesPoints is an interface to represent point location
-// queries.
+// queries. Once built, a point locator is read-only: queries
+// do not modify it, so it may be queried from any number of
+// goroutines at once. Locators built on search trees only
+// look up their trees with lookups which leave them unchanged,
+// even for splay trees, which restructure on tree.BST.Search.
+//
+// PointLocate returns the face of the located dcel containing
+// the query point. Points in no other face, including points
+// outside the bounds of the dcel, are in its outer face,
+// dc.Faces[dcel.OUTER_FACE], which is returned for them rather
+// than nil. An error is only returned for malformed queries,
+// such as those with fewer than two values.
 type LocatesPoints interface {
 	PointLocate(vs ...float64) (*dcel.Face, error)
 }
+
+// BatchLocatesPoints is an interface to represent point
+// locators which can answer many queries at once.
+type BatchLocatesPoints interface {
+	LocatesPoints
+	BatchLocate(pts []geom.D2) ([]*dcel.Face, error)
+}
+
+// BatchLocate point locates each of pts with lp, spreading the
+// queries across goroutines. The ith face returned is the face
+// containing pts[i]. If any query fails, the error of the first
+// failing query in pts is returned.
+func BatchLocate(lp LocatesPoints, pts []geom.D2) ([]*dcel.Face, error) {
+	fs := make([]*dcel.Face, len(pts))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(pts) {
+		workers = len(pts)
+	}
+	if workers == 0 {
+		return fs, nil
+	}
+	// Each worker takes a contiguous chunk of pts, so the
+	// first error among the chunks, in order, is the first
+	// error in pts.
+	errs := make([]error, workers)
+	chunk := (len(pts) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		end := start + chunk
+		if end > len(pts) {
+			end = len(pts)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				f, err := lp.PointLocate(pts[i].X(), pts[i].Y())
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				fs[i] = f
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+// ShootsRays is an interface to represent vertical ray
+// shooting queries. EdgeAbove returns the first edge hit by a
+// ray shot upward from the query point, and EdgeBelow the first
+// edge hit by a ray shot downward. Of the two half edges making
+// up the edge hit, the one whose face is toward the query point
+// is returned. If the ray hits no edge, nil is returned.
+type ShootsRays interface {
+	EdgeAbove(x, y float64) *dcel.Edge
+	EdgeBelow(x, y float64) *dcel.Edge
+}
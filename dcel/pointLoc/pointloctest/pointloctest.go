@@ -0,0 +1,512 @@
+// pointloctest holds a conformance suite which point locators
+// can run from their tests, checking their answers against
+// those of the plumb line in bruteForce.
+
+package pointloctest
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/off"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// A Builder builds a point locator over a dcel.
+type Builder func(*dcel.DCEL) (pointLoc.LocatesPoints, error)
+
+var (
+	// randomSeeds is the number of random dcels of each
+	// size checked, and randomSizes the splits made to
+	// build them, as with dcel.Random2DDCELWithSeed.
+	randomSeeds = 20
+	randomSizes = []int{0, 1, 2, 3, 5, 10, 25, 50}
+	randomRange = 10000.0
+	// fuzzSplits bounds the splits of the dcels of the
+	// fuzz corpus, as maxFuzzSplits does in FuzzLocators.
+	fuzzSplits = 20
+	// queryCt is the number of random points
+	// queried over each dcel.
+	queryCt = 500
+	// boxCt is the number of points queried
+	// along each side of the bounds of a dcel.
+	boxCt = 5
+	// maxReports is the number of failing queries reported
+	// for each dcel before the rest are only counted.
+	maxReports = 5
+)
+
+// Run checks the point locators made by build against the plumb
+// line of bruteForce, over random dcels, the OFF files in demo/data,
+// the fuzz corpus and OFF test data of this repository, and shapes
+// whose vertices and edges line up, as in grids and rectangles.
+// Points inside faces must be placed in the same face as the plumb
+// line places them, and points outside every bounded face in the
+// outer face. Points on vertices and edges may be placed in any
+// face whose boundary holds them, but not in the outer face unless
+// it is one of those. If a locator also classifies points, as a
+// pointLoc.ClassifiesPoints, its Locations must match those of
+// the plumb line exactly.
+func Run(t *testing.T, build Builder) {
+	t.Run("Random", func(t *testing.T) { runRandom(t, build) })
+	t.Run("OFF", func(t *testing.T) { runOFF(t, build) })
+	t.Run("Corpus", func(t *testing.T) { runCorpus(t, build) })
+	t.Run("Degenerate", func(t *testing.T) { runDegenerate(t, build) })
+}
+
+func runRandom(t *testing.T, build Builder) {
+	seeds, queries := randomSeeds, queryCt
+	if testing.Short() {
+		seeds, queries = 2, queryCt/10
+	}
+	for _, size := range randomSizes {
+		for seed := int64(0); seed < int64(seeds); seed++ {
+			name := fmt.Sprintf("Random2DDCELWithSeed(%v, %d, %d)", randomRange, size, seed)
+			dc := dcel.Random2DDCELWithSeed(randomRange, size, seed)
+			rnd := rand.New(rand.NewSource(seed))
+			Check(t, name, dc, build, Points(rnd, dc, queries))
+		}
+	}
+}
+
+// repoDir returns the path of the root of this repository,
+// found relative to the source of this package.
+func repoDir() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..", "..")
+}
+
+// dataDir returns the path of demo/data.
+func dataDir() string {
+	return filepath.Join(repoDir(), "demo", "data")
+}
+
+// runOFF checks each OFF file in demo/data which loads, as
+// the subdivision it describes or, if it describes a polyhedron,
+// as the arrangement of its edges seen from above. See planar.
+func runOFF(t *testing.T, build Builder) {
+	files, _ := filepath.Glob(filepath.Join(dataDir(), "*.off"))
+	if len(files) == 0 {
+		t.Skip("no OFF files found in demo/data")
+	}
+	t.Logf("checked %d of %d OFF files", checkOFF(t, build, files), len(files))
+}
+
+// runCorpus checks the dcels of the fuzz corpus of FuzzLocators,
+// in dcel/pointLoc/test, and the OFF files kept as test data there
+// and in dcel/off. Like FuzzLocators, it checks the dcel of each
+// corpus entry after every split.
+func runCorpus(t *testing.T, build Builder) {
+	test := filepath.Join(repoDir(), "dcel", "pointLoc", "test", "testdata")
+	entries, _ := filepath.Glob(filepath.Join(test, "fuzz", "FuzzLocators", "*"))
+	for _, entry := range entries {
+		seed, splits, err := fuzzEntry(entry)
+		if err != nil {
+			t.Errorf("%s: %v", entry, err)
+			continue
+		}
+		for n := 0; n <= int(splits)%(fuzzSplits+1); n++ {
+			name := fmt.Sprintf("Random2DDCELWithSeed(%v, %d, %d)", randomRange, n, seed)
+			dc := dcel.Random2DDCELWithSeed(randomRange, n, seed)
+			Check(t, name, dc, build, Points(rand.New(rand.NewSource(seed)), dc, queryCt))
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join(test, "*.off"))
+	offFiles, _ := filepath.Glob(filepath.Join(repoDir(), "dcel", "off", "testdata", "*.off"))
+	files = append(files, offFiles...)
+	t.Logf("checked %d fuzz corpus entries and %d of %d OFF files",
+		len(entries), checkOFF(t, build, files), len(files))
+}
+
+// fuzzEntry reads the seed and splits of a
+// FuzzLocators corpus entry.
+func fuzzEntry(file string) (seed int64, splits uint8, err error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, 0, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != "go test fuzz v1" {
+		return 0, 0, fmt.Errorf("not a corpus entry of two values")
+	}
+	vals := [2]uint64{}
+	for i, line := range lines[1:] {
+		open, close := strings.Index(line, "("), strings.LastIndex(line, ")")
+		if open < 0 || close < open {
+			return 0, 0, fmt.Errorf("malformed value %q", line)
+		}
+		v := line[open+1 : close]
+		// Bytes may be written as characters, as in byte('\x07').
+		if c, err := strconv.Unquote(v); err == nil && len(c) == 1 {
+			vals[i] = uint64(c[0])
+			continue
+		}
+		n, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed value %q", line)
+		}
+		vals[i] = uint64(n)
+	}
+	return int64(vals[0]), uint8(vals[1]), nil
+}
+
+// checkOFF checks each of files which loads and has a planar
+// subdivision, returning how many were checked.
+func checkOFF(t *testing.T, build Builder, files []string) int {
+	rnd := rand.New(rand.NewSource(1))
+	checked := 0
+	for _, file := range files {
+		dc, err := off.Load(file)
+		if err != nil {
+			continue
+		}
+		if dc, ok := planar(dc); ok {
+			Check(t, filepath.Base(file), dc, build, Points(rnd, dc, queryCt))
+			checked++
+		}
+	}
+	return checked
+}
+
+// planar returns the plane subdivision dc describes. If dc fails
+// to validate, has vertices off the plane z = 0, beyond ε, or has
+// edges which cross, it describes a polyhedron or another shape
+// which point location does not cover, and the arrangement of
+// its edges, as dcel.FromSegments builds it from their x and y
+// values, is returned instead. If the subdivision has vertices
+// whose x or y values differ by less than ε, which the sweeps of
+// slab and trapezoid treat as lined up, ok is false. OFF files
+// may list faces either way around, so faces listed against the
+// direction of dcel.Random2DDCEL's faces are reversed.
+func planar(dc *dcel.DCEL) (*dcel.DCEL, bool) {
+	if len(dc.Validate()) == 0 && flat(dc) {
+		if si, err := dc.SelfIntersections(); err == nil && len(si) == 0 {
+			wind(dc)
+			return dc, resolved(dc)
+		}
+	}
+	segs := make([]geom.FullEdge, 0, len(dc.HalfEdges)/2)
+	for i := 0; i < len(dc.HalfEdges); i += 2 {
+		a, b := dc.HalfEdges[i].Origin, dc.HalfEdges[i].Twin.Origin
+		segs = append(segs, geom.FullEdge{
+			geom.NewPoint(a.X(), a.Y(), 0),
+			geom.NewPoint(b.X(), b.Y(), 0)})
+	}
+	arr, err := dcel.FromSegments(segs)
+	if err != nil || len(arr.Validate()) != 0 {
+		return nil, false
+	}
+	return arr, resolved(arr)
+}
+
+// flat reports whether the vertices of dc lie within ε
+// of z = 0, and if so moves them onto it.
+func flat(dc *dcel.DCEL) bool {
+	for _, v := range dc.Vertices {
+		if !geom.F64eq(v.Z(), 0) {
+			return false
+		}
+	}
+	for _, v := range dc.Vertices {
+		v.Point[2] = 0
+	}
+	return true
+}
+
+// resolved reports whether the vertices of dc which do not
+// share an x or y value are more than ε apart in it.
+func resolved(dc *dcel.DCEL) bool {
+	for d := 0; d < 2; d++ {
+		vs := make([]float64, len(dc.Vertices))
+		for i, v := range dc.Vertices {
+			vs[i] = v.Val(d)
+		}
+		sort.Float64s(vs)
+		for i := 1; i < len(vs); i++ {
+			if vs[i] != vs[i-1] && geom.F64eq(vs[i], vs[i-1]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// wind reverses every edge of dc if its faces run the other
+// way around from those of dcel.Random2DDCEL. Unlike
+// dcel.CorrectDirectionalityAll, which turns each face
+// around on its own, this keeps faces which share edges
+// on either side of them.
+func wind(dc *dcel.DCEL) {
+	if len(dc.Faces) <= dcel.OUTER_FACE+1 {
+		return
+	}
+	clock, err := dc.Faces[dcel.OUTER_FACE+1].Outer.IsClockwise()
+	if err != nil || !clock {
+		return
+	}
+	next := make(map[*dcel.Edge]*dcel.Edge, len(dc.HalfEdges))
+	face := make(map[*dcel.Edge]*dcel.Face, len(dc.HalfEdges))
+	for _, e := range dc.HalfEdges {
+		// Each boundary is taken over by the twins of its edges,
+		// in reverse, so the edge after e is the twin of the
+		// edge which was before e's twin.
+		next[e] = e.Twin.Prev.Twin
+		face[e] = e.Twin.Face
+	}
+	for _, e := range dc.HalfEdges {
+		e.Face = face[e]
+		e.SetNext(next[e])
+	}
+	for _, f := range dc.Faces {
+		if f.Outer != nil {
+			f.Outer = f.Outer.Twin
+		}
+		for i, e := range f.Inner {
+			f.Inner[i] = e.Twin
+		}
+	}
+}
+
+// holesOFF is a square with a square hole, which
+// holds an island.
+const holesOFF = `OFF
+12 3 0
+0 0 0
+0 90 0
+90 90 0
+90 0 0
+30 30 0
+30 60 0
+60 60 0
+60 30 0
+40 40 0
+40 50 0
+50 50 0
+50 40 0
+4 0 1 2 3
+4 4 5 6 7
+4 8 9 10 11
+`
+
+func runDegenerate(t *testing.T, build Builder) {
+	shapes := map[string]*dcel.DCEL{
+		"rect":         dcel.Rect(0, 0, 100, 50),
+		"triangle":     triangle(),
+		"grid":         grid(4),
+		"vertical fan": fan(),
+	}
+	if dc, err := off.Read(strings.NewReader(holesOFF)); err == nil {
+		shapes["holes"] = dc
+	} else {
+		t.Error("holes:", err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for name, dc := range shapes {
+		Check(t, name, dc, build, Points(rnd, dc, queryCt/5))
+	}
+}
+
+// triangle returns a triangle with a vertical side.
+func triangle() *dcel.DCEL {
+	return dcel.Polygon([]geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(0, 60, 0),
+		geom.NewPoint(80, 20, 0),
+	})
+}
+
+// grid returns an n by n grid of unit squares scaled by 10,
+// whose vertices share x and y values.
+func grid(n int) *dcel.DCEL {
+	size := float64(n * 10)
+	segs := []geom.FullEdge{}
+	for i := 0; i <= n; i++ {
+		v := float64(i * 10)
+		segs = append(segs,
+			geom.FullEdge{geom.NewPoint(0, v, 0), geom.NewPoint(size, v, 0)},
+			geom.FullEdge{geom.NewPoint(v, 0, 0), geom.NewPoint(v, size, 0)})
+	}
+	dc, _ := dcel.FromSegments(segs)
+	return dc
+}
+
+// fan returns triangles around a shared vertex, split
+// by a vertical edge through it.
+func fan() *dcel.DCEL {
+	c := geom.NewPoint(50, 50, 0)
+	ring := []geom.Point{
+		geom.NewPoint(50, 0, 0),
+		geom.NewPoint(100, 20, 0),
+		geom.NewPoint(100, 80, 0),
+		geom.NewPoint(50, 100, 0),
+		geom.NewPoint(0, 80, 0),
+		geom.NewPoint(0, 20, 0),
+	}
+	segs := []geom.FullEdge{}
+	for i, p := range ring {
+		segs = append(segs,
+			geom.FullEdge{p, ring[(i+1)%len(ring)]},
+			geom.FullEdge{c, p})
+	}
+	dc, _ := dcel.FromSegments(segs)
+	return dc
+}
+
+// Points returns the points Run queries over dc: n points
+// spread over the bounds of dc and a margin around it, drawn
+// from rnd, points along the sides of those bounds, and the
+// points on and around its vertices and edges where locators
+// are most likely to fail.
+func Points(rnd *rand.Rand, dc *dcel.DCEL, n int) []geom.D2 {
+	pts := append(randomPts(rnd, dc, n), boxPts(rnd, dc)...)
+	return append(pts, borderPts(rnd, dc)...)
+}
+
+// boxPts returns the corners of the bounds of dc, and boxCt
+// points along each of its sides. Where dc is bounded by a
+// rectangle, as dcel.Random2DDCEL is, these lie on its edges.
+func boxPts(rnd *rand.Rand, dc *dcel.DCEL) []geom.D2 {
+	minX, minY, maxX, maxY := dc.MinX(), dc.MinY(), dc.MaxX(), dc.MaxY()
+	pts := []geom.D2{
+		geom.NewPoint(minX, minY, 0),
+		geom.NewPoint(minX, maxY, 0),
+		geom.NewPoint(maxX, minY, 0),
+		geom.NewPoint(maxX, maxY, 0),
+	}
+	for i := 0; i < boxCt; i++ {
+		x := minX + rnd.Float64()*(maxX-minX)
+		y := minY + rnd.Float64()*(maxY-minY)
+		pts = append(pts,
+			geom.NewPoint(x, minY, 0),
+			geom.NewPoint(x, maxY, 0),
+			geom.NewPoint(minX, y, 0),
+			geom.NewPoint(maxX, y, 0))
+	}
+	return pts
+}
+
+// randomPts returns n points spread over the bounds of
+// dc and a margin around it.
+func randomPts(rnd *rand.Rand, dc *dcel.DCEL, n int) []geom.D2 {
+	minX, minY, maxX, maxY := dc.MinX(), dc.MinY(), dc.MaxX(), dc.MaxY()
+	w, h := maxX-minX, maxY-minY
+	pts := make([]geom.D2, n)
+	for i := range pts {
+		pts[i] = geom.NewPoint(
+			minX-w/10+rnd.Float64()*w*1.2,
+			minY-h/10+rnd.Float64()*h*1.2, 0)
+	}
+	return append(pts,
+		geom.NewPoint(minX-1, minY-1, 0),
+		geom.NewPoint(maxX+1, maxY+1, 0),
+		geom.NewPoint(-1e9, -1e9, 0),
+		geom.NewPoint(1e9, 1e9, 0))
+}
+
+// borderPts returns points where locators are most likely to
+// fail: the vertices of dc, the midpoints of its edges, and
+// points directly above and below its vertices. Midpoints which
+// rounding moves off their edges are left out, as which face
+// they are in is too close to call.
+func borderPts(rnd *rand.Rand, dc *dcel.DCEL) []geom.D2 {
+	minY, maxY := dc.MinY(), dc.MaxY()
+	pts := []geom.D2{}
+	for _, v := range dc.Vertices {
+		pts = append(pts, v,
+			geom.NewPoint(v.X(), v.Y()+(maxY-v.Y())*rnd.Float64(), 0),
+			geom.NewPoint(v.X(), v.Y()-(v.Y()-minY)*rnd.Float64(), 0))
+	}
+	for i := 0; i < len(dc.HalfEdges); i += 2 {
+		e := dc.HalfEdges[i]
+		a, b := e.Origin, e.Twin.Origin
+		mid := geom.NewPoint((a.X()+b.X())/2, (a.Y()+b.Y())/2, 0)
+		if geom.Orient2D(a, b, mid) == 0 {
+			pts = append(pts, mid)
+		}
+	}
+	return pts
+}
+
+// Check builds a point locator over dc with build, and checks
+// its answers for each of pts against the plumb line, as Run
+// does. name identifies dc in failure messages.
+func Check(t *testing.T, name string, dc *dcel.DCEL, build Builder, pts []geom.D2) {
+	t.Helper()
+	pl, err := build(dc)
+	if err != nil {
+		t.Errorf("%s: building failed: %v", name, err)
+		return
+	}
+	if pl == nil {
+		t.Errorf("%s: built a nil locator", name)
+		return
+	}
+	if _, err := pl.PointLocate(1); err == nil {
+		t.Errorf("%s: no error for a query with one value", name)
+	}
+	ref := bruteForce.PlumbLine(dc).(pointLoc.ClassifiesPoints)
+	cp, classifies := pl.(pointLoc.ClassifiesPoints)
+	fails := 0
+	fail := func(format string, args ...interface{}) {
+		fails++
+		if fails <= maxReports {
+			t.Errorf(name+": "+format, args...)
+		}
+	}
+	for _, p := range pts {
+		x, y := p.X(), p.Y()
+		want, _ := ref.Locate(x, y)
+		got, err := pl.PointLocate(x, y)
+		switch {
+		case err != nil:
+			fail("(%v, %v): %v", x, y, err)
+			continue
+		case got == nil:
+			fail("(%v, %v): nil face", x, y)
+			continue
+		case want.Kind == pointLoc.InFace && got != want.Face:
+			fail("(%v, %v): face %d, want %d", x, y, dc.ScanFaces(got), dc.ScanFaces(want.Face))
+		case want.Kind != pointLoc.InFace && !touches(dc, want, got):
+			fail("(%v, %v): face %d does not touch the point, %v", x, y, dc.ScanFaces(got), want.Kind)
+		}
+		if !classifies {
+			continue
+		}
+		loc, err := cp.Locate(x, y)
+		if err != nil {
+			fail("(%v, %v): Locate: %v", x, y, err)
+		} else if loc != want {
+			fail("(%v, %v): Locate gave %v, want %v", x, y, loc.Kind, want.Kind)
+		}
+	}
+	if fails > maxReports {
+		t.Errorf("%s: %d more failures", name, fails-maxReports)
+	}
+}
+
+// touches reports whether f has loc, which is on
+// an edge or vertex of dc, on its boundary.
+func touches(dc *dcel.DCEL, loc pointLoc.Location, f *dcel.Face) bool {
+	if loc.Kind == pointLoc.OnEdge {
+		return f == loc.Edge.Face || f == loc.Edge.Twin.Face
+	}
+	for _, e := range dc.HalfEdges {
+		if e.Origin == loc.Vertex && (e.Face == f || e.Twin.Face == f) {
+			return true
+		}
+	}
+	return false
+}
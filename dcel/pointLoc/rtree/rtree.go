@@ -1,15 +1,17 @@
 package rtree
 
 import (
-	"fmt"
-
 	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/Sythe2o0/rtreego"
 )
 
-func DCELtoRtree(dc *dcel.DCEL) *Rtree {
+// DCELtoRtree returns an Rtree over the bounding boxes of dc's
+// faces. If a Tracer is given, each face whose bounding box a
+// query falls in is traced to it.
+func DCELtoRtree(dc *dcel.DCEL, tr ...pointLoc.Tracer) *Rtree {
 	//tree := rtreego.NewTree(20, 40)
 	tree := rtreego.NewTree(3, 9)
 
@@ -17,7 +19,7 @@ func DCELtoRtree(dc *dcel.DCEL) *Rtree {
 		tree.Insert(&SpatialFace{dc.Faces[i]})
 	}
 
-	return &Rtree{tree}
+	return &Rtree{tree, dc.Faces[dcel.OUTER_FACE], pointLoc.OptionalTracer(tr)}
 }
 
 type SpatialFace struct {
@@ -30,20 +32,24 @@ func (sf *SpatialFace) Bounds() *rtreego.Rect {
 	diff := span.Diff()
 	p := rtreego.Point{min.X(), min.Y(), min.Z()}
 	dist := [3]float64{diff.X(), diff.Y(), diff.Z()}
+	// Spatial gives Bounds no way to report an error, so
+	// every side is made positive, which NewRect requires.
 	for i, v := range dist {
-		if v <= 0 {
+		if !(v > 0) {
 			dist[i] = 0.1
 		}
 	}
-	rect, err := rtreego.NewRect(p, dist)
-	if err != nil {
-		fmt.Println("bounds error:", err)
-	}
+	rect, _ := rtreego.NewRect(p, dist)
 	return &rect
 }
 
+// Rtree is a point locator over the bounding boxes of a
+// DCEL's faces. It may be queried concurrently, so long
+// as no faces are inserted at the same time.
 type Rtree struct {
 	*rtreego.Rtree
+	outerFace *dcel.Face
+	tracer    pointLoc.Tracer
 }
 
 func (rt *Rtree) PointLocate(vs ...float64) (*dcel.Face, error) {
@@ -54,28 +60,62 @@ func (rt *Rtree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) > 2 {
 		pt = pt.Set(2, vs[2]).(geom.Point)
 	}
-	fs := SearchIntersect(rt.Rtree, pt)
+	fs, err := SearchIntersect(rt.Rtree, pt, rt.tracer)
+	if err != nil {
+		return nil, err
+	}
 	if len(fs) > 0 {
 		return fs[0], nil
 	}
-	return nil, nil
+	// Plumb line may count points on the boundaries of faces
+	// outside all of them, so the faces near pt are checked
+	// for a vertex or edge at pt.
+	near, err := nearby(rt.Rtree, pt)
+	if err != nil {
+		return nil, err
+	}
+	loc := pointLoc.Classify(pt, rt.outerFace, pointLoc.FaceEdges(near...)...)
+	return loc.Touching(rt.outerFace), nil
+}
+
+// BatchLocate point locates each of pts concurrently,
+// returning the face containing each point in order.
+func (rt *Rtree) BatchLocate(pts []geom.D2) ([]*dcel.Face, error) {
+	return pointLoc.BatchLocate(rt, pts)
 }
 
 // SearchIntersect filters the output of rtree.SearchIntersect
-// on plumb line contains for the tree's faces
-func SearchIntersect(tree *rtreego.Rtree, p geom.D3) []*dcel.Face {
+// on plumb line contains for the tree's faces, tracing each
+// face checked to the given Tracer, if any. Any error
+// building the query box around p is returned.
+func SearchIntersect(tree *rtreego.Rtree, p geom.D3, tr ...pointLoc.Tracer) ([]*dcel.Face, error) {
+	tracer := pointLoc.OptionalTracer(tr)
+	fs, err := nearby(tree, p)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*dcel.Face, 0)
+	for _, f := range fs {
+		tracer.FaceChecked(f)
+		if f.Contains(p) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// nearby returns the faces of tree whose
+// bounding boxes hold the query box around p.
+func nearby(tree *rtreego.Rtree, p geom.D3) ([]*dcel.Face, error) {
 	pt := rtreego.Point{p.X(), p.Y(), p.Z()}
 	rect, err := rtreego.NewRect(pt, [3]float64{0.1, 0.1, 0.1})
 	if err != nil {
-		fmt.Println("bounds error:", err)
+		return nil, err
 	}
 	spts := tree.SearchIntersect(&rect)
-	out := make([]*dcel.Face, 0)
-	for _, s := range spts {
-		sf := s.(*SpatialFace)
-		if sf.Contains(p) {
-			out = append(out, sf.Face)
-		}
+	fs := make([]*dcel.Face, len(spts))
+	for i, s := range spts {
+		fs[i] = s.(*SpatialFace).Face
 	}
-	return out
+	return fs, nil
 }
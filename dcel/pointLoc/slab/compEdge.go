@@ -1,10 +1,8 @@
 package slab
 
 import (
-	"fmt"
-
 	"github.com/nylen/go-compgeo/dcel"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/search"
 )
@@ -46,15 +44,18 @@ func (sn shellNode) Val() search.Equalable {
 	return sn.v
 }
 
+// A compEdge is an edge in a slab, which traces
+// each comparison it makes to tracer.
 type compEdge struct {
 	*dcel.Edge
+	tracer pointLoc.Tracer
 }
 
 func (ce compEdge) Compare(i interface{}) search.CompareResult {
 	switch c := i.(type) {
 	case compEdge:
-		visualize.DrawLine(ce.Edge.Origin, ce.Edge.Twin.Origin)
-		visualize.DrawLine(c.Edge.Origin, c.Edge.Twin.Origin)
+		ce.tracer.EdgeChecked(ce.Edge.Origin, ce.Edge.Twin.Origin)
+		ce.tracer.EdgeChecked(c.Edge.Origin, c.Edge.Twin.Origin)
 		if ce.Edge == c.Edge {
 			return search.Equal
 		}
@@ -63,26 +64,51 @@ func (ce compEdge) Compare(i interface{}) search.CompareResult {
 			geom.F64eq(ce.Twin.X(), c.Twin.X()) && geom.F64eq(ce.Twin.Y(), c.Twin.Y()) {
 			return search.Equal
 		}
-		compX, err := ce.FindSharedPoint(c.Edge, 0)
-		if err != nil {
-			fmt.Println("Edges share no point on x axis")
-		}
-		p1, err := ce.PointAt(0, compX)
-		if err != nil {
-			fmt.Println(ce, " Did not have a value at ", compX)
-		}
-		p2, err := c.PointAt(0, compX)
-		if err != nil {
-			fmt.Println(c, " Did not have a value at ", compX)
+		if r, ok := ce.below(c); ok {
+			return r
 		}
+		// Edges in one slab share some span of x values,
+		// so the errors here cannot occur.
+		compX, _ := ce.FindSharedPoint(c.Edge, 0)
+		p1, _ := ce.PointAt(0, compX)
+		p2, _ := c.PointAt(0, compX)
 		if p1[1] < p2[1] {
 			return search.Less
 		}
 		if p1[1] > p2[1] {
 			return search.Greater
 		}
-		fmt.Println("Something went wrong")
 		return search.Greater
 	}
 	return ce.Edge.Compare(i)
 }
+
+// below compares two edges in one slab, which do not cross, by
+// which side of one edge the other's endpoints lie on, using the
+// edge whose left end is further left, so that the other's left
+// end is within its span. It reports false if either edge is
+// vertical, or if the two edges lie along one line.
+func (ce compEdge) below(c compEdge) (search.CompareResult, bool) {
+	al, ar := ce.Low(0).(geom.D2), ce.High(0).(geom.D2)
+	bl, br := c.Low(0).(geom.D2), c.High(0).(geom.D2)
+	if al.X() == ar.X() || bl.X() == br.X() {
+		return search.Equal, false
+	}
+	flip := false
+	if al.X() < bl.X() {
+		al, ar, bl, br = bl, br, al, ar
+		flip = true
+	}
+	o := geom.Orient2D(bl, br, al)
+	if o == 0 {
+		o = geom.Orient2D(bl, br, ar)
+	}
+	if o == 0 {
+		return search.Equal, false
+	}
+	// al is above bl -> br if the three run counter-clockwise.
+	if (o > 0) != flip {
+		return search.Greater, true
+	}
+	return search.Less, true
+}
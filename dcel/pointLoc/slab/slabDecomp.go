@@ -4,11 +4,11 @@ package slab
 
 import (
 	"fmt"
+	"sort"
 
 	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
 	"github.com/nylen/go-compgeo/dcel/pointLoc"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/search"
 	"github.com/nylen/go-compgeo/search/tree"
@@ -18,7 +18,11 @@ import (
 // point location.
 // The real difficulties in Slab Decomposition are all in the
 // persistent bst itself, so this is a fairly simple function.
-func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error) {
+// If a Tracer is given, the sweep and the queries on the returned
+// locator are traced to it. Queries only use SearchUp and
+// SearchDown on the slabs, which do not modify them, so any
+// bstType, including tree.Splay, may be queried concurrently.
+func Decompose(dc *dcel.DCEL, bstType tree.Type, tr ...pointLoc.Tracer) (*PointLocator, error) {
 	if dc == nil || len(dc.Vertices) < 3 {
 		return nil, compgeo.BadDCELError{}
 	}
@@ -28,17 +32,19 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 		// applications so we don't reject that idea offhand.
 		return nil, compgeo.BadDimensionError{}
 	}
+	tracer := pointLoc.OptionalTracer(tr)
 	t := tree.New(bstType).ToPersistent()
 	pts := dc.VerticesSorted(0)
+	xs := []float64{}
 
 	i := 0
 	for i < len(pts) {
 		p := pts[i]
 		v := dc.Vertices[p]
 		// Set the BST's instant to the x value of this point
-		visualize.HighlightColor = visualize.CheckLineColor
-		visualize.DrawVerticalLine(v)
+		tracer.SweepLine(v)
 		t.SetInstant(v.X())
+		xs = append(xs, v.X())
 		ct := t.ThisInstant()
 
 		// Aggregate all points at this x value so we do not
@@ -64,20 +70,14 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 			le = append(le, leftEdges...)
 			re = append(re, rightEdges...)
 		}
-		fmt.Println("Left Edges", le)
-		fmt.Println("Right Edges", re)
 		// Remove all edges from the PersistentBST connecting to the left
 		// of the points
-		visualize.HighlightColor = visualize.RemoveColor
 		for _, e := range le {
-			fmt.Println("Removing", e.Twin)
-			err := ct.Delete(shellNode{compEdge{e.Twin}, search.Nil{}})
-			fmt.Println("Remove result", err)
-			fmt.Println(ct)
+			tracer.EdgeRemoved(e.Twin.Origin, e.Origin)
+			ct.Delete(shellNode{compEdge{e.Twin, tracer}, search.Nil{}})
 		}
 		// Add all edges to the PersistentBST connecting to the right
 		// of the point
-		visualize.HighlightColor = visualize.AddColor
 		for _, e := range re {
 			// We always want the half edge that points to the right,
 			// and between the two faces this edge is on we want the
@@ -85,22 +85,25 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 			// locate to the edge above the query point. Returning an
 			// edge for a query represents that the query is below
 			// the edge,
-			fmt.Println("Adding", e)
-			ct.Insert(shellNode{compEdge{e}, faces{e.Face, e.Twin.Face}})
-			fmt.Println(ct)
+			tracer.EdgeAdded(e.Origin, e.Twin.Origin)
+			ct.Insert(shellNode{compEdge{e, tracer}, faces{e.Face, e.Twin.Face}})
 		}
 
 		i++
 	}
-	visualize.HighlightColor = visualize.CheckLineColor
-	return &PointLocator{t, dc.Faces[dcel.OUTER_FACE]}, nil
+	return &PointLocator{t, xs, dc.Faces[dcel.OUTER_FACE], tracer}, nil
 }
 
 // PointLocator is a construct that uses slab
-// decomposition for point location.
+// decomposition for point location. Queries only read
+// from past instants of its persistent tree, so once
+// Decompose returns it may be queried concurrently.
 type PointLocator struct {
-	dp        search.DynamicPersistent
+	dp search.DynamicPersistent
+	// xs holds the x value at which each slab starts.
+	xs        []float64
 	outerFace *dcel.Face
+	tracer    pointLoc.Tracer
 }
 
 func (spl *PointLocator) String() string {
@@ -108,32 +111,37 @@ func (spl *PointLocator) String() string {
 }
 
 // PointLocate returns which face within this SlabPointLocator
-// the query point lands, within two dimensions.
+// the query point lands, within two dimensions. Points on the
+// boundaries of faces are placed in a face touching them.
 func (spl *PointLocator) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
 	}
-	fmt.Println("Querying", vs)
-	tree := spl.dp.AtInstant(vs[0])
-	fmt.Println("Tree found:")
-	fmt.Println(tree)
 	p := geom.Point{vs[0], vs[1], 0}
+	f := spl.face(p)
+	// Plumb line may count points on the boundaries
+	// of faces outside all of them.
+	if f == spl.outerFace {
+		return spl.locate(p, f).Touching(f), nil
+	}
+	return f, nil
+}
 
+// face returns the face whose interior p is in,
+// or the outer face if p is in no face's interior.
+func (spl *PointLocator) face(p geom.Point) *dcel.Face {
+	tree := spl.dp.AtInstant(p.X())
 	e, f := tree.SearchDown(p, 0)
 	if e == nil {
-		fmt.Println("Location on empty tree")
-		return nil, nil
+		return spl.outerFace
 	}
 	e2, f2 := tree.SearchUp(p, 0)
-	fmt.Println("Edges found", e, e2)
 	if geom.VerticalCompare(p, e.(compEdge)) == search.Greater {
-		fmt.Println(p, "is above edge", e)
-		return nil, nil
+		return spl.outerFace
 	}
 
 	if geom.VerticalCompare(p, e2.(compEdge)) == search.Less {
-		fmt.Println(p, "is below edge", e2)
-		return nil, nil
+		return spl.outerFace
 	}
 
 	// We then do PIP on each face, and return
@@ -144,15 +152,83 @@ func (spl *PointLocator) PointLocate(vs ...float64) (*dcel.Face, error) {
 
 	for _, f5 := range faces {
 		if f5 != spl.outerFace {
-			fmt.Println("Checking if face contains", p)
-			visualize.HighlightColor = visualize.CheckFaceColor
-			visualize.DrawFace(f5)
+			spl.tracer.FaceChecked(f5)
 			if f5.Contains(p) {
-				fmt.Println("P was contained")
-				return f5, nil
+				return f5
 			}
 		}
 	}
 
-	return nil, nil
+	return spl.outerFace
+}
+
+// BatchLocate point locates each of pts concurrently,
+// returning the face containing each point in order.
+func (spl *PointLocator) BatchLocate(pts []geom.D2) ([]*dcel.Face, error) {
+	return pointLoc.BatchLocate(spl, pts)
+}
+
+// Locate returns where (x, y) lies, telling points on
+// vertices and edges apart from points inside faces.
+func (spl *PointLocator) Locate(x, y float64) (pointLoc.Location, error) {
+	p := geom.Point{x, y, 0}
+	return spl.locate(p, spl.face(p)), nil
+}
+
+// locate returns where p lies, or p inside f
+// if p is on no vertex or edge.
+func (spl *PointLocator) locate(p geom.Point, f *dcel.Face) pointLoc.Location {
+	x := p.X()
+	// Slabs are open to the right, so edges which end at x are
+	// only in the slab before x. Vertical edges are in no slab,
+	// but are reached from the ends of the edges found.
+	tree := spl.dp.AtInstant(x)
+	edges := []*dcel.Edge{above(tree, p), below(tree, p)}
+	if i := sort.SearchFloat64s(spl.xs, x); i > 0 {
+		tree = spl.dp.AtInstant(spl.xs[i-1])
+		edges = append(edges, above(tree, p), below(tree, p))
+	}
+	return pointLoc.Classify(p, f, edges...)
+}
+
+// EdgeAbove returns the right-pointing half edge directly
+// above (x, y), whose face lies beneath it, or nil if there is
+// no edge above (x, y).
+func (spl *PointLocator) EdgeAbove(x, y float64) *dcel.Edge {
+	return above(spl.dp.AtInstant(x), geom.Point{x, y, 0})
+}
+
+// EdgeBelow returns the left-pointing half edge directly
+// below (x, y), whose face lies above it, or nil if there is
+// no edge below (x, y).
+func (spl *PointLocator) EdgeBelow(x, y float64) *dcel.Edge {
+	return below(spl.dp.AtInstant(x), geom.Point{x, y, 0})
+}
+
+// above returns the right-pointing half edge
+// directly above p in the slab tree.
+func above(tree search.Dynamic, p geom.Point) *dcel.Edge {
+	e, _ := tree.SearchUp(p, 0)
+	if e == nil {
+		return nil
+	}
+	// SearchUp rounds down to the highest edge if
+	// p is above every edge.
+	if geom.VerticalCompare(p, e.(compEdge)) == search.Less {
+		return nil
+	}
+	return e.(compEdge).Edge
+}
+
+// below returns the left-pointing half edge
+// directly below p in the slab tree.
+func below(tree search.Dynamic, p geom.Point) *dcel.Edge {
+	e, _ := tree.SearchDown(p, 0)
+	if e == nil {
+		return nil
+	}
+	if geom.VerticalCompare(p, e.(compEdge)) == search.Greater {
+		return nil
+	}
+	return e.(compEdge).Twin
 }
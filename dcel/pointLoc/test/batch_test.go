@@ -0,0 +1,62 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBatchLocate(t *testing.T, bl pointLoc.BatchLocatesPoints, limit int) {
+	pts := make([]geom.D2, limit)
+	for i := range pts {
+		pts[i] = randomPt()
+	}
+	fs, err := bl.BatchLocate(pts)
+	assert.Nil(t, err)
+	if !assert.Equal(t, len(pts), len(fs)) {
+		return
+	}
+	for i, p := range pts {
+		f, err := bl.PointLocate(p.X(), p.Y())
+		assert.Nil(t, err)
+		if !assert.Equal(t, f, fs[i]) {
+			t.Log("Error point:", p)
+		}
+	}
+}
+
+func TestRandomDCELBatchLocate(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+
+	sl, err := slab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	testBatchLocate(t, sl, testCt)
+
+	_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	testBatchLocate(t, tr, testCt)
+
+	// Splay trees restructure on lookups, but slabs are
+	// only searched up and down, which does not splay them.
+	sl, err = slab.Decompose(dc, tree.Splay)
+	assert.Nil(t, err)
+	testBatchLocate(t, sl, testCt)
+
+	testBatchLocate(t, rtree.DCELtoRtree(dc), testCt)
+	testBatchLocate(t, bruteForce.PlumbLine(dc).(pointLoc.BatchLocatesPoints), testCt)
+}
+
+func TestBatchLocateEmpty(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+	fs, err := bruteForce.PlumbLine(dc).(pointLoc.BatchLocatesPoints).BatchLocate(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, fs)
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel/delaunay"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateDelaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		pts := make([]geom.D2, 100)
+		for j := range pts {
+			pts[j] = geom.NewPoint(rng.Float64()*inputRange, rng.Float64()*inputRange, 0)
+		}
+		dc, err := delaunay.TriangulateWithRand(pts, rng)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		testMainLocators(t, dc, 1000)
+	}
+}
+
+func TestLocateVoronoi(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	bounds := geom.NewSpan(geom.NewPoint(0, 0, 0), geom.NewPoint(inputRange, inputRange, 0))
+	for i := 0; i < 5; i++ {
+		sites := make([]geom.D2, 100)
+		for j := range sites {
+			sites[j] = geom.NewPoint(rng.Float64()*inputRange, rng.Float64()*inputRange, 0)
+		}
+		dc, cells, err := delaunay.Voronoi(sites, bounds)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		testMainLocators(t, dc, 1000)
+		// The slab decomposition finds the nearest site.
+		sl, err := slab.Decompose(dc, tree.RedBlack)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		for j := 0; j < 1000; j++ {
+			pt := randomPt()
+			f, err := sl.PointLocate(pt.X(), pt.Y())
+			assert.Nil(t, err)
+			best, dist := 0, -1.0
+			for k, s := range sites {
+				dx, dy := s.X()-pt.X(), s.Y()-pt.Y()
+				if dist < 0 || dx*dx+dy*dy < dist {
+					best, dist = k, dx*dx+dy*dy
+				}
+			}
+			assert.Equal(t, cells[best], f, "at %v", pt)
+		}
+	}
+}
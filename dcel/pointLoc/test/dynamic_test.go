@@ -0,0 +1,212 @@
+package test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// yBeside finds by brute force the y value of the segment in
+// segs nearest to p vertically, above p if up is true and
+// otherwise below it, or def if there is no such segment.
+func yBeside(segs []geom.FullEdge, p geom.D2, up bool, def float64) float64 {
+	best := def
+	for _, fe := range segs {
+		a, b := fe[0], fe[1]
+		if a.X() == b.X() || p.X() < math.Min(a.X(), b.X()) || p.X() > math.Max(a.X(), b.X()) {
+			continue
+		}
+		y := a.Y() + (b.Y()-a.Y())*(p.X()-a.X())/(b.X()-a.X())
+		if up && y > p.Y() && y < best || !up && y < p.Y() && y > best {
+			best = y
+		}
+	}
+	return best
+}
+
+// yAlong returns the y value of fe at x.
+func yAlong(fe geom.FullEdge, x float64) float64 {
+	a, b := fe[0], fe[1]
+	if a.X() == b.X() {
+		return a.Y()
+	}
+	return a.Y() + (b.Y()-a.Y())*(x-a.X())/(b.X()-a.X())
+}
+
+// testTrapezoids checks that the trapezoids found by querying
+// tn are bounded by the segments directly above and below each
+// query point.
+func testTrapezoids(t *testing.T, tn *trapezoid.Node, segs []geom.FullEdge, limit int) {
+	for i := 0; i < limit; i++ {
+		pt := randomPt()
+		trs := tn.Query(geom.FullEdge{pt.(geom.Point), pt.(geom.Point)})
+		if !assert.Equal(t, 1, len(trs)) {
+			continue
+		}
+		top := yAlong(trs[0].TopEdge(), pt.X())
+		bot := yAlong(trs[0].BotEdge(), pt.X())
+		expTop := yBeside(segs, pt, true, inputRange+1)
+		expBot := yBeside(segs, pt, false, -1)
+		if !assert.InDelta(t, expTop, top, 1e-6) ||
+			!assert.InDelta(t, expBot, bot, 1e-6) {
+			t.Log("Error point:", pt)
+			t.Log("Error trapezoid:", trs[0])
+		}
+	}
+}
+
+func TestDynamicTrapezoidalMap(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+	_, _, tn, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	segs, faces, err := dc.FullEdges()
+	assert.Nil(t, err)
+
+	// Remove every edge, checking the map as it shrinks
+	order := rand.Perm(len(segs))
+	remaining := append([]geom.FullEdge{}, segs...)
+	for _, i := range order {
+		assert.Nil(t, tn.DeleteEdge(segs[i], dc.Faces[dcel.OUTER_FACE]))
+		for j, fe := range remaining {
+			if fe == segs[i] {
+				remaining = append(remaining[:j], remaining[j+1:]...)
+				break
+			}
+		}
+		testTrapezoids(t, tn, remaining, 50)
+	}
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.DeleteEdge(segs[0], nil))
+
+	// Then put them all back, in another order
+	for _, i := range rand.Perm(len(segs)) {
+		assert.Nil(t, tn.InsertEdge(segs[i], faces[i]))
+		remaining = append(remaining, segs[i])
+		testTrapezoids(t, tn, remaining, 50)
+	}
+	errs := 0
+	testRandomPts(t, tn, testCt/10, &errs)
+	assert.Equal(t, 0, errs)
+}
+
+func TestDynamicTrapezoidalMapErrors(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, 0)
+	_, _, tn, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	pt := func(x, y float64) geom.Point {
+		return geom.NewPoint(x, y, 0)
+	}
+	fe := geom.FullEdge{pt(100, 100), pt(900, 500)}
+	assert.Nil(t, tn.InsertEdge(fe, [2]*dcel.Face{}))
+	// Duplicates, crossings, and segments through
+	// endpoints or out of bounds are rejected.
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.InsertEdge(fe, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.InsertEdge(geom.FullEdge{pt(100, 500), pt(900, 100)}, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.InsertEdge(geom.FullEdge{pt(500, 300), pt(500, 900)}, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.InsertEdge(geom.FullEdge{pt(50, 50), pt(150, 150)}, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.InsertEdge(geom.FullEdge{pt(5, 5), pt(5, 5)}, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.RangeError{}, tn.InsertEdge(geom.FullEdge{pt(-500, 5), pt(5, 5)}, [2]*dcel.Face{}))
+	assert.Equal(t, compgeo.RangeError{}, tn.InsertEdge(geom.FullEdge{pt(5, 5), pt(5, inputRange*2)}, [2]*dcel.Face{}))
+	// Segments sharing an endpoint are fine
+	assert.Nil(t, tn.InsertEdge(geom.FullEdge{pt(900, 500), pt(100, 900)}, [2]*dcel.Face{}))
+
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.DeleteEdge(geom.FullEdge{pt(1, 1), pt(2, 2)}, nil))
+	assert.Nil(t, tn.DeleteEdge(fe, nil))
+	assert.Equal(t, compgeo.BadEdgeError{}, tn.DeleteEdge(fe, nil))
+}
+
+func TestDynamicTrapezoidalMapFaces(t *testing.T) {
+	pt := func(x, y float64) geom.Point {
+		return geom.NewPoint(x, y, 0)
+	}
+	// A strip split into three faces, the first two of
+	// which are merged by removing the edge between them.
+	fe := geom.FullEdge{pt(100, 0), pt(120, 100)}
+	segs := []geom.FullEdge{
+		{pt(0, 0), pt(300, 0)},
+		{pt(300, 0), pt(300, 100)},
+		{pt(300, 100), pt(0, 100)},
+		{pt(0, 100), pt(0, 0)},
+		{pt(200, 0), pt(210, 100)},
+	}
+	dc, err := dcel.FromSegments(append(segs, fe))
+	assert.Nil(t, err)
+	merged, err := dcel.FromSegments(segs)
+	assert.Nil(t, err)
+	_, _, tn, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	mergedFace, err := bruteForce.PlumbLine(merged).PointLocate(50, 50)
+	assert.Nil(t, err)
+	assert.Nil(t, tn.DeleteEdge(fe, mergedFace))
+
+	// Points away from fe should be found in the
+	// merged face too, and those beyond it should not.
+	for _, p := range []geom.Point{pt(110, 50), pt(50, 50), pt(5, 95), pt(180, 10), pt(195, 90)} {
+		f, err := tn.PointLocate(p.X(), p.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, mergedFace, f, "at %v", p)
+	}
+	want, err := bruteForce.PlumbLine(dc).PointLocate(250, 50)
+	assert.Nil(t, err)
+	f, err := tn.PointLocate(250, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, want, f)
+	assert.NotEqual(t, mergedFace, f)
+}
+
+func TestDynamicTrapezoidalMapInsertFaces(t *testing.T) {
+	pt := func(x, y float64) geom.Point {
+		return geom.NewPoint(x, y, 0)
+	}
+	// The strip of TestDynamicTrapezoidalMapFaces, merged by
+	// removing fe and then split again by putting it back.
+	fe := geom.FullEdge{pt(100, 0), pt(120, 100)}
+	segs := []geom.FullEdge{
+		{pt(0, 0), pt(300, 0)},
+		{pt(300, 0), pt(300, 100)},
+		{pt(300, 100), pt(0, 100)},
+		{pt(0, 100), pt(0, 0)},
+		{pt(200, 0), pt(210, 100)},
+	}
+	dc, err := dcel.FromSegments(append(segs, fe))
+	assert.Nil(t, err)
+	merged, err := dcel.FromSegments(segs)
+	assert.Nil(t, err)
+	_, _, tn, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	mergedFace, err := bruteForce.PlumbLine(merged).PointLocate(50, 50)
+	assert.Nil(t, err)
+	assert.Nil(t, tn.DeleteEdge(fe, mergedFace))
+
+	pl := bruteForce.PlumbLine(dc)
+	left, err := pl.PointLocate(50, 50)
+	assert.Nil(t, err)
+	right, err := pl.PointLocate(150, 50)
+	assert.Nil(t, err)
+	assert.NotEqual(t, left, right)
+	assert.Nil(t, tn.InsertEdge(fe, [2]*dcel.Face{left, right}))
+
+	// Points away from fe on either side should be found in
+	// the face on their side, not the merged face.
+	for _, p := range []geom.Point{pt(50, 50), pt(5, 95), pt(105, 90)} {
+		f, err := tn.PointLocate(p.X(), p.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, left, f, "at %v", p)
+	}
+	for _, p := range []geom.Point{pt(150, 50), pt(180, 10), pt(195, 90), pt(115, 5)} {
+		f, err := tn.PointLocate(p.X(), p.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, right, f, "at %v", p)
+	}
+	want, err := pl.PointLocate(250, 50)
+	assert.Nil(t, err)
+	f, err := tn.PointLocate(250, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, want, f)
+}
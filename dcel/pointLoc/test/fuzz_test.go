@@ -0,0 +1,81 @@
+package test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/off"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/pointloctest"
+	"github.com/nylen/go-compgeo/printutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// maxFuzzSplits bounds the splits of the dcels fuzzed,
+// as each is checked after every split.
+const maxFuzzSplits = 20
+
+// FuzzLocators checks every locator of builders against the
+// plumb line over dcels made by Random2DDCELWithSeed. The fuzzer
+// does not shrink numbers, so each dcel is checked after every
+// split, and the first to fail, which is the smallest crasher
+// for its seed, is saved in testdata as an OFF file. From then
+// on, TestCorpus checks it.
+func FuzzLocators(f *testing.F) {
+	for seed := int64(0); seed < 4; seed++ {
+		f.Add(seed, uint8(3))
+		f.Add(seed, uint8(25))
+	}
+	f.Fuzz(func(t *testing.T, seed int64, splits uint8) {
+		// Random2DDCELWithSeed draws each split in turn, so
+		// with fewer splits it makes the first steps of the
+		// same dcel.
+		for n := 0; n <= int(splits)%(maxFuzzSplits+1); n++ {
+			dc := dcel.Random2DDCELWithSeed(inputRange, n, seed)
+			name := "Random2DDCELWithSeed(" + printutil.Stringf64(inputRange) + ", " +
+				strconv.Itoa(n) + ", " + strconv.FormatInt(seed, 10) + ")"
+			checkAll(t, name, dc, rand.New(rand.NewSource(seed)))
+			if t.Failed() {
+				of := off.Save(dc)
+				of.Comments = append(of.Comments, name)
+				file := filepath.Join("testdata",
+					"random-"+strconv.Itoa(n)+"-"+strconv.FormatInt(seed, 10)+".off")
+				err := os.MkdirAll("testdata", 0755)
+				if err == nil {
+					err = of.WriteFile(file)
+				}
+				if err != nil {
+					t.Log("Could not save crasher:", err)
+				} else {
+					t.Log("Saved crasher:", file)
+				}
+				return
+			}
+		}
+	})
+}
+
+// TestCorpus checks every locator of builders over
+// the dcels FuzzLocators has saved in testdata.
+func TestCorpus(t *testing.T) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "*.off"))
+	for _, file := range files {
+		dc, err := off.Load(file)
+		if !assert.Nil(t, err, file) {
+			continue
+		}
+		checkAll(t, filepath.Base(file), dc, rand.New(rand.NewSource(1)))
+	}
+}
+
+// checkAll checks each locator of builders over dc
+// against the plumb line.
+func checkAll(t *testing.T, name string, dc *dcel.DCEL, rnd *rand.Rand) {
+	pts := pointloctest.Points(rnd, dc, 50)
+	for locator, build := range builders {
+		pointloctest.Check(t, locator+" over "+name, dc, build, pts)
+	}
+}
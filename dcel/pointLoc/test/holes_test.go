@@ -0,0 +1,137 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/off"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// holesOFF holds a square A with two holes: a courtyard B,
+// which itself holds an island C, and a second courtyard D.
+// E lies apart from A. Nested faces come before the faces
+// around them, so nesting cannot rely on face order.
+const holesOFF = `OFF
+20 5 0
+0 0 0
+0 100 0
+100 100 0
+100 0 0
+20 20 0
+20 60 0
+60 60 0
+60 20 0
+35 35 0
+35 45 0
+45 45 0
+45 35 0
+70 70 0
+70 90 0
+90 90 0
+90 70 0
+200 0 0
+200 50 0
+250 50 0
+250 0 0
+4 8 9 10 11
+4 0 1 2 3
+4 12 13 14 15
+4 4 5 6 7
+4 16 17 18 19
+`
+
+// Indices of the faces of holesOFF in the loaded dcel
+const (
+	holeC = iota + 1
+	holeA
+	holeD
+	holeB
+	holeE
+)
+
+var holeQueries = []struct {
+	pt   geom.Point
+	face int
+}{
+	{geom.Point{10, 10, 0}, holeA},
+	{geom.Point{65, 65, 0}, holeA},
+	{geom.Point{95, 50, 0}, holeA},
+	{geom.Point{30, 30, 0}, holeB},
+	{geom.Point{50, 25, 0}, holeB},
+	{geom.Point{40, 40, 0}, holeC},
+	{geom.Point{80, 80, 0}, holeD},
+	{geom.Point{225, 25, 0}, holeE},
+	{geom.Point{150, 50, 0}, dcel.OUTER_FACE},
+	{geom.Point{50, 150, 0}, dcel.OUTER_FACE},
+}
+
+func loadHoles(t *testing.T) *dcel.DCEL {
+	dc, err := off.Read(strings.NewReader(holesOFF))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return dc
+}
+
+func TestOFFHoles(t *testing.T) {
+	dc := loadHoles(t)
+	assert.Empty(t, dc.Validate())
+	assert.Len(t, dc.Faces[holeA].Inner, 2)
+	assert.Len(t, dc.Faces[holeB].Inner, 1)
+	assert.Len(t, dc.Faces[holeC].Inner, 0)
+	assert.Len(t, dc.Faces[holeD].Inner, 0)
+	assert.Len(t, dc.Faces[dcel.OUTER_FACE].Inner, 2)
+	for _, e := range dc.Faces[holeA].Inner {
+		for _, e2 := range e.EdgeChain() {
+			assert.Equal(t, dc.Faces[holeA], e2.Face)
+		}
+	}
+	for _, q := range holeQueries {
+		for i, f := range dc.Faces {
+			assert.Equal(t, i == q.face, f.Contains(q.pt), q.pt, i)
+		}
+	}
+	bounds := dc.Faces[holeA].Bounds()
+	assert.Equal(t, geom.Point{0, 0, 0}, bounds.At(geom.SPAN_MIN))
+	assert.Equal(t, geom.Point{100, 100, 0}, bounds.At(geom.SPAN_MAX))
+
+	// Holes survive being saved and read back
+	file := filepath.Join(t.TempDir(), "holes.off")
+	assert.Nil(t, off.Save(dc).WriteFile(file))
+	dc2, err := off.Load(file)
+	if assert.Nil(t, err) {
+		assert.Len(t, dc2.Faces[holeA].Inner, 2)
+	}
+}
+
+func TestTriangulateHoles(t *testing.T) {
+	dc := loadHoles(t)
+	tri, mp, err := monotone.Triangulate(dc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, f := range tri.Faces[dcel.OUTER_FACE+1:] {
+		vs := f.Vertices()
+		assert.Len(t, vs, 3)
+		c := geom.NewPoint(
+			(vs[0].X()+vs[1].X()+vs[2].X())/3,
+			(vs[0].Y()+vs[1].Y()+vs[2].Y())/3, 0)
+		assert.True(t, mp[f].Contains(c), c)
+	}
+}
+
+func TestLocateHoles(t *testing.T) {
+	dc := loadHoles(t)
+	for name, pl := range allLocators(t, dc) {
+		for _, q := range holeQueries {
+			f, err := pl.PointLocate(q.pt.X(), q.pt.Y())
+			assert.Nil(t, err, name)
+			assert.Equal(t, dc.Faces[q.face], f, "%s at %v", name, q.pt)
+		}
+	}
+}
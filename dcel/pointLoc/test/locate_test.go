@@ -0,0 +1,107 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClassify checks that each locator of dc finds its
+// vertices, the midpoints of its edges which lie exactly on
+// them, and random points inside its faces.
+func testClassify(t *testing.T, dc *dcel.DCEL) {
+	locators := map[string]pointLoc.ClassifiesPoints{}
+	sl, err := slab.Decompose(dc, tree.RedBlack)
+	if !assert.Nil(t, err) {
+		return
+	}
+	locators["slab"] = sl
+	_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	locators["trapezoid"] = tr
+	locators["plumb line"] = bruteForce.PlumbLine(dc).(pointLoc.ClassifiesPoints)
+
+	for name, l := range locators {
+		for _, v := range dc.Vertices {
+			loc, err := l.Locate(v.X(), v.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, pointLoc.OnVertex, loc.Kind, "%s at %v", name, v)
+			assert.Equal(t, v, loc.Vertex, "%s at %v", name, v)
+		}
+		for i := 0; i < len(dc.HalfEdges); i += 2 {
+			e := dc.HalfEdges[i]
+			a, b := e.Origin, e.Twin.Origin
+			mid := geom.NewPoint((a.X()+b.X())/2, (a.Y()+b.Y())/2, 0)
+			if geom.Orient2D(a, b, mid) != 0 {
+				continue
+			}
+			if b.X() < a.X() || (b.X() == a.X() && b.Y() < a.Y()) {
+				e = e.Twin
+			}
+			loc, err := l.Locate(mid.X(), mid.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, pointLoc.OnEdge, loc.Kind, "%s at %v", name, mid)
+			assert.Equal(t, e, loc.Edge, "%s at %v", name, mid)
+			assert.Equal(t, e.Face, loc.Face, "%s at %v", name, mid)
+		}
+	}
+	pl := bruteForce.PlumbLine(dc)
+	for j := 0; j < 200; j++ {
+		pt := randomPt()
+		expected, _ := pl.PointLocate(pt.X(), pt.Y())
+		for name, l := range locators {
+			loc, err := l.Locate(pt.X(), pt.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, pointLoc.InFace, loc.Kind, "%s at %v", name, pt)
+			assert.Equal(t, dc.ScanFaces(expected), dc.ScanFaces(loc.Face), "%s at %v", name, pt)
+		}
+	}
+}
+
+func TestClassifyRandom(t *testing.T) {
+	for i := int64(0); i < 10; i++ {
+		testClassify(t, dcel.Random2DDCELWithSeed(inputRange, 25, i))
+	}
+}
+
+func TestClassifyGrid(t *testing.T) {
+	// A grid, with diagonals across some of its cells, and
+	// vertical edges, whose midpoints are exact.
+	segs := []geom.FullEdge{}
+	for i := 0.0; i <= 4; i++ {
+		segs = append(segs,
+			geom.FullEdge{geom.NewPoint(0, i*2000, 0), geom.NewPoint(8000, i*2000, 0)},
+			geom.FullEdge{geom.NewPoint(i*2000, 0, 0), geom.NewPoint(i*2000, 8000, 0)})
+	}
+	segs = append(segs,
+		geom.FullEdge{geom.NewPoint(0, 0, 0), geom.NewPoint(4000, 4000, 0)},
+		geom.FullEdge{geom.NewPoint(4000, 8000, 0), geom.NewPoint(8000, 4000, 0)})
+	dc, err := dcel.FromSegments(segs)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	testClassify(t, dc)
+}
+
+func TestClassifyVerticalChain(t *testing.T) {
+	// A triangle whose vertical side is split by vertices with no
+	// other edges, so points along it are only reached by following
+	// that side from the ends of the edges above and below them.
+	testClassify(t, dcel.Polygon([]geom.D2{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(0, 2000, 0),
+		geom.NewPoint(0, 4000, 0),
+		geom.NewPoint(0, 6000, 0),
+		geom.NewPoint(0, 8000, 0),
+		geom.NewPoint(8000, 4000, 0),
+	}))
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// notched is a square with a notch cut into its top and its
+// bottom, so it has both a split and a merge vertex.
+var notched = []geom.D2{
+	geom.NewPoint(0, 0, 0),
+	geom.NewPoint(2, 0, 0),
+	geom.NewPoint(3, 2, 0),
+	geom.NewPoint(4, 0, 0),
+	geom.NewPoint(6, 0, 0),
+	geom.NewPoint(6, 6, 0),
+	geom.NewPoint(4, 6, 0),
+	geom.NewPoint(3, 4, 0),
+	geom.NewPoint(2, 6, 0),
+	geom.NewPoint(0, 6, 0),
+}
+
+// zigzag is y-monotone, but not convex
+// along either of its chains.
+var zigzag = []geom.D2{
+	geom.NewPoint(0, 0, 0),
+	geom.NewPoint(2, 2, 0),
+	geom.NewPoint(1, 4, 0),
+	geom.NewPoint(3, 6, 0),
+	geom.NewPoint(0, 8, 0),
+	geom.NewPoint(-3, 6, 0),
+	geom.NewPoint(-1, 4, 0),
+	geom.NewPoint(-2, 2, 0),
+}
+
+// faceArea returns the unsigned area of f's outer chain.
+func faceArea(f *dcel.Face) float64 {
+	area := 0.0
+	for _, e := range f.Outer.EdgeChain() {
+		a, b := e.Origin, e.Next.Origin
+		area += a.X()*b.Y() - b.X()*a.Y()
+	}
+	return math.Abs(area / 2)
+}
+
+// yMonotone reports whether f's outer chain has a single
+// highest and lowest vertex, ordering vertices by y, then x.
+func yMonotone(f *dcel.Face) bool {
+	less := func(a, b *dcel.Vertex) bool {
+		return a.Y() < b.Y() || a.Y() == b.Y() && a.X() < b.X()
+	}
+	peaks := 0
+	for _, e := range f.Outer.EdgeChain() {
+		p, v, n := e.Prev.Origin, e.Origin, e.Next.Origin
+		if less(p, v) && less(n, v) {
+			peaks++
+		}
+	}
+	return peaks == 1
+}
+
+// testPieces checks that the faces of dc past the outer face
+// cover area, are each mapped to orig by mp, and each pass ok.
+func testPieces(t *testing.T, dc *dcel.DCEL, mp map[*dcel.Face]*dcel.Face,
+	orig *dcel.Face, area float64, ok func(*dcel.Face) bool) {
+	assert.Empty(t, dc.Validate())
+	covered := 0.0
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		assert.True(t, ok(f), "%v", f.Vertices())
+		assert.Equal(t, orig, mp[f])
+		covered += faceArea(f)
+	}
+	assert.InDelta(t, area, covered, 1e-9)
+}
+
+func TestSplitMonotone(t *testing.T) {
+	dc := dcel.Polygon(notched)
+	split, mp, err := monotone.Split(dc)
+	if !assert.Nil(t, err) || !assert.NotNil(t, split) {
+		return
+	}
+	assert.True(t, len(split.Faces) > 2)
+	testPieces(t, split, mp, dc.Faces[1], 32, yMonotone)
+}
+
+func TestTriangulateSplit(t *testing.T) {
+	triangle := func(f *dcel.Face) bool {
+		return len(f.Vertices()) == 3
+	}
+	// Without a faceMap, the pieces of a face
+	// are mapped to the face they were split from.
+	dc := dcel.Polygon(zigzag)
+	tri, mp, err := monotone.TriangulateSplit(dc, nil)
+	if assert.Nil(t, err) {
+		assert.Len(t, tri.Faces, len(zigzag)-1)
+		testPieces(t, tri, mp, dc.Faces[1], 24, triangle)
+	}
+
+	dc = dcel.Polygon(notched)
+	tri, mp, err = monotone.Triangulate(dc)
+	if assert.Nil(t, err) {
+		assert.Len(t, tri.Faces, len(notched)-1)
+		testPieces(t, tri, mp, dc.Faces[1], 32, triangle)
+	}
+}
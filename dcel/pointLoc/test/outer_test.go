@@ -0,0 +1,93 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// allLocators returns each point locator of builders built over dc.
+func allLocators(t *testing.T, dc *dcel.DCEL) map[string]pointLoc.LocatesPoints {
+	locators := map[string]pointLoc.LocatesPoints{}
+	for name, build := range builders {
+		pl, err := build(dc)
+		assert.Nil(t, err, name)
+		locators[name] = pl
+	}
+	return locators
+}
+
+// testOuterFace checks that each of locators places each of
+// outside in the outer face of dc, and never returns nil.
+func testOuterFace(t *testing.T, dc *dcel.DCEL, locators map[string]pointLoc.LocatesPoints, outside []geom.Point) {
+	outer := dc.Faces[dcel.OUTER_FACE]
+	for name, pl := range locators {
+		for _, p := range outside {
+			f, err := pl.PointLocate(p.X(), p.Y())
+			assert.Nil(t, err, name)
+			assert.True(t, f == outer, "%s at %v", name, p)
+		}
+		for i := 0; i < 100; i++ {
+			p := randomPt()
+			f, err := pl.PointLocate(p.X(), p.Y())
+			assert.Nil(t, err, name)
+			assert.NotNil(t, f, "%s at %v", name, p)
+		}
+		_, err := pl.PointLocate(1)
+		assert.NotNil(t, err, name)
+	}
+}
+
+// beyond returns points outside the bounds of dc,
+// on each side of it and far from it.
+func beyond(dc *dcel.DCEL) []geom.Point {
+	minX, minY, maxX, maxY := dc.MinX(), dc.MinY(), dc.MaxX(), dc.MaxY()
+	midX, midY := (minX+maxX)/2, (minY+maxY)/2
+	return []geom.Point{
+		geom.NewPoint(minX-1, midY, 0),
+		geom.NewPoint(maxX+1, midY, 0),
+		geom.NewPoint(midX, minY-1, 0),
+		geom.NewPoint(midX, maxY+1, 0),
+		geom.NewPoint(maxX+1, maxY+1, 0),
+		geom.NewPoint(-1e9, -1e9, 0),
+		geom.NewPoint(1e9, 1e9, 0),
+	}
+}
+
+func TestOuterFaceRandom(t *testing.T) {
+	for i := int64(0); i < 5; i++ {
+		dc := dcel.Random2DDCELWithSeed(inputRange, 25, i)
+		testOuterFace(t, dc, allLocators(t, dc), beyond(dc))
+	}
+}
+
+func TestOuterFaceConcave(t *testing.T) {
+	// A C shape, whose notch is part of the outer face
+	// inside the bounds of the dcel.
+	dc, err := dcel.FromSegments([]geom.FullEdge{
+		{geom.NewPoint(0, 0, 0), geom.NewPoint(10000, 0, 0)},
+		{geom.NewPoint(10000, 0, 0), geom.NewPoint(10000, 2000, 0)},
+		{geom.NewPoint(10000, 2000, 0), geom.NewPoint(2000, 2000, 0)},
+		{geom.NewPoint(2000, 2000, 0), geom.NewPoint(2000, 8000, 0)},
+		{geom.NewPoint(2000, 8000, 0), geom.NewPoint(10000, 8000, 0)},
+		{geom.NewPoint(10000, 8000, 0), geom.NewPoint(10000, 10000, 0)},
+		{geom.NewPoint(10000, 10000, 0), geom.NewPoint(0, 10000, 0)},
+		{geom.NewPoint(0, 10000, 0), geom.NewPoint(0, 0, 0)},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	locators := allLocators(t, dc)
+	dit, err := monotone.NewDoubleIntervalTree(dc.Faces[1], dc)
+	assert.Nil(t, err)
+	locators["double interval tree"] = dit
+	outside := append(beyond(dc),
+		geom.NewPoint(5000, 5000, 0),
+		geom.NewPoint(9999, 7999, 0),
+		geom.NewPoint(2001, 2001, 0))
+	testOuterFace(t, dc, locators, outside)
+}
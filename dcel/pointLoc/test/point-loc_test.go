@@ -2,32 +2,41 @@ package test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/nylen/go-compgeo/dcel"
 	"github.com/nylen/go-compgeo/dcel/off"
 	"github.com/nylen/go-compgeo/dcel/pointLoc"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/bench/bruteForce"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/bench/slab"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/bench/trapezoid"
+	benchBruteForce "github.com/nylen/go-compgeo/dcel/pointLoc/bench/bruteForce"
+	benchSlab "github.com/nylen/go-compgeo/dcel/pointLoc/bench/slab"
+	benchTrapezoid "github.com/nylen/go-compgeo/dcel/pointLoc/bench/trapezoid"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/kirkpatrick"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/pointloctest"
 	"github.com/nylen/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/search/tree"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
-	inputSize   = 25
-	inputRange  = 10000.0
-	testCt      = 10000
-	slabErrors  = 0
-	trapErrors  = 0
-	rtreeErrors = 0
-	plumbErrors = 0
-	seed        int64
+	inputSize  = 25
+	inputRange = 10000.0
+	testCt     = 10000
+	seed       int64
+	treeTypes  = []tree.Type{tree.AVL, tree.RedBlack, tree.Splay,
+		tree.Treap, tree.Scapegoat, tree.AA}
+	treeNames = []string{"AVL", "RedBlack", "Splay",
+		"Treap", "Scapegoat", "AA"}
 )
 
 func randomPt() geom.D3 {
@@ -35,6 +44,40 @@ func randomPt() geom.D3 {
 		rand.Float64()*inputRange, 0)
 }
 
+// builders builds each point locator checked by pointloctest.
+var builders = map[string]pointloctest.Builder{
+	"plumb line": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return bruteForce.PlumbLine(dc), nil
+	},
+	"rtree": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return rtree.DCELtoRtree(dc), nil
+	},
+	"slab": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return slab.Decompose(dc, tree.RedBlack)
+	},
+	"trapezoid": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+		return tr, err
+	},
+	"kirkpatrick monotone": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return kirkpatrick.TriangleTree(dc, kirkpatrick.MONOTONE)
+	},
+	"kirkpatrick trapezoid": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return kirkpatrick.TriangleTree(dc, kirkpatrick.TRAPEZOID)
+	},
+	"kirkpatrick delaunay": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return kirkpatrick.TriangleTree(dc, kirkpatrick.DELAUNAY)
+	},
+}
+
+func TestConformance(t *testing.T) {
+	for name, build := range builders {
+		t.Run(name, func(t *testing.T) {
+			pointloctest.Run(t, build)
+		})
+	}
+}
+
 func testRandomPts(t *testing.T, pl pointLoc.LocatesPoints, limit int, errs *int) {
 	for i := 0; i < limit; i++ {
 		pt := randomPt()
@@ -50,97 +93,66 @@ func testRandomPts(t *testing.T, pl pointLoc.LocatesPoints, limit int, errs *int
 	}
 }
 
-func TestRandomDCELSlab(t *testing.T) {
+func TestRandomDCELSlabTypes(t *testing.T) {
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
-
-	structure, err := slab.Decompose(dc, tree.RedBlack)
-	assert.Nil(t, err)
-
-	testRandomPts(t, structure, testCt, &slabErrors)
-	printErrors()
-}
-
-func TestDCELSlabErrors(t *testing.T) {
-	errCt := 0
-	subTestCt := 100
-	for i := 0; i < testCt; i++ {
-		inputSize = 2
-		dc := dcel.Random2DDCEL(inputRange, inputSize)
-		structure, err := slab.Decompose(dc, tree.RedBlack)
-		if err != nil {
-			errCt++
-			continue
-		}
-		queryErrors := 0
-		testRandomPts(t, structure, subTestCt, &queryErrors)
-		if queryErrors != 0 {
-			off.Save(dc).WriteFile("testFail" + strconv.Itoa(i) + ".off")
-			t.Log("Error index:", i)
-			s := structure.(*slab.PointLocator)
-			t.Log(s)
-			errCt++
-			continue
-		}
+	for _, typ := range treeTypes {
+		structure, err := slab.Decompose(dc, typ)
+		assert.Nil(t, err)
+		errs := 0
+		testRandomPts(t, structure, testCt/10, &errs)
+		assert.Equal(t, 0, errs)
 	}
-	t.Log("Errors in Slab:", errCt, testCt)
 }
 
-func TestRandomDCELTrapErrors(t *testing.T) {
-	errCt := 0
-	subTestCt := 50
-	for i := 0; i < testCt; i++ {
-		inputSize = 3
-		dc := dcel.Random2DDCEL(inputRange, inputSize)
-		_, _, structure, err := trapezoid.TrapezoidalMap(dc)
-		if err != nil {
-			errCt++
-			continue
-		}
-		queryErrors := 0
-		testRandomPts(t, structure, subTestCt, &queryErrors)
-		if queryErrors != 0 {
-			errCt++
-			continue
+func TestRandom2DDCELWithSeed(t *testing.T) {
+	dc1 := dcel.Random2DDCELWithSeed(inputRange, 25, 1)
+	dc2 := dcel.Random2DDCELWithSeed(inputRange, 25, 1)
+	assert.Equal(t, off.Save(dc1), off.Save(dc2))
+
+	// A seed recorded in an OFF file should be written to
+	// it as a comment, which Load skips over.
+	of := off.Save(dc1)
+	of.Comments = []string{"seed 1"}
+	file := filepath.Join(t.TempDir(), "seeded.off")
+	assert.Nil(t, of.WriteFile(file))
+	data, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	comments := []string{}
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(l, "#") {
+			comments = append(comments, l)
 		}
 	}
-	t.Log("Errors in Trap:", errCt, testCt)
-}
-
-func TestRandomDCELTrapezoid(t *testing.T) {
-	dc := dcel.Random2DDCEL(inputRange, inputSize)
-
-	_, _, structure, err := trapezoid.TrapezoidalMap(dc)
+	assert.Equal(t, []string{"# seed 1"}, comments)
+	dc3, err := off.Load(file)
 	assert.Nil(t, err)
-
-	testRandomPts(t, structure, testCt, &trapErrors)
-	printErrors()
-}
-
-func TestRandomDCELPlumbLine(t *testing.T) {
-	dc := dcel.Random2DDCEL(inputRange, inputSize)
-
-	it := bruteForce.PlumbLine(dc)
-
-	testRandomPts(t, it, testCt, &plumbErrors)
-	printErrors()
+	assert.Equal(t, len(dc1.Faces), len(dc3.Faces))
+	assert.Equal(t, len(dc1.Vertices), len(dc3.Vertices))
 }
 
-func TestRandomDCELRtree(t *testing.T) {
+func TestTrapezoidalMapWithRand(t *testing.T) {
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
-
-	pl := rtree.DCELtoRtree(dc)
-
-	testRandomPts(t, pl, testCt, &rtreeErrors)
-	printErrors()
-}
-
-func printErrors() {
-	fmt.Println("Total errors")
-	fmt.Println("Slab:", slabErrors)
-	fmt.Println("Trapezoid:", trapErrors)
-	fmt.Println("Rtree:", rtreeErrors)
-	fmt.Println("Plumb Line:", plumbErrors, "(Baseline)")
-	fmt.Println()
+	_, _, expected, err := trapezoid.TrapezoidalMapWithRand(dc, rand.New(rand.NewSource(1)))
+	assert.Nil(t, err)
+	// Maps built at once with equally seeded sources
+	// should match the map built alone.
+	workers := 4
+	maps := make([]*trapezoid.Node, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, maps[i], _ = trapezoid.TrapezoidalMapWithRand(dc, rand.New(rand.NewSource(1)))
+		}(i)
+	}
+	wg.Wait()
+	for _, m := range maps {
+		assert.Equal(t, expected.String(), m.String())
+	}
+	errs := 0
+	testRandomPts(t, expected, testCt/10, &errs)
+	assert.Equal(t, 0, errs)
 }
 
 func BenchmarkRandomDCELSlab(b *testing.B) {
@@ -151,7 +163,7 @@ func BenchmarkRandomDCELSlab(b *testing.B) {
 	rand.Seed(seed)
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
 
-	pl, _ := slab.Decompose(dc, tree.RedBlack)
+	pl, _ := benchSlab.Decompose(dc, tree.RedBlack)
 
 	rand.Seed(seed)
 	b.ResetTimer()
@@ -172,7 +184,7 @@ func BenchmarkRandomDCELTrapezoid(b *testing.B) {
 	rand.Seed(seed)
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
 
-	_, _, pl, _ := trapezoid.TrapezoidalMap(dc)
+	_, _, pl, _ := benchTrapezoid.TrapezoidalMap(dc)
 
 	rand.Seed(seed)
 	b.ResetTimer()
@@ -200,6 +212,24 @@ func BenchmarkRandomDCELRtree(b *testing.B) {
 	}
 }
 
+func BenchmarkRandomDCELKirkpatrick(b *testing.B) {
+	if seed == 0 {
+		fmt.Println("Setting seed")
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+
+	pl, _ := kirkpatrick.TriangleTree(dc, kirkpatrick.MONOTONE)
+
+	rand.Seed(seed)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pt := randomPt()
+		pl.PointLocate(pt.X(), pt.Y())
+	}
+}
+
 func BenchmarkRandomDCELPlumbLine(b *testing.B) {
 	if seed == 0 {
 		fmt.Println("Setting seed")
@@ -208,7 +238,7 @@ func BenchmarkRandomDCELPlumbLine(b *testing.B) {
 	rand.Seed(seed)
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
 
-	pl := bruteForce.PlumbLine(dc)
+	pl := benchBruteForce.PlumbLine(dc)
 
 	rand.Seed(seed)
 	b.ResetTimer()
@@ -226,7 +256,26 @@ func BenchmarkRandomSetupSlab(b *testing.B) {
 	rand.Seed(seed)
 	for i := 0; i < b.N; i++ {
 		dc := dcel.Random2DDCEL(inputRange, inputSize)
-		slab.Decompose(dc, tree.RedBlack)
+		benchSlab.Decompose(dc, tree.RedBlack)
+	}
+}
+
+// BenchmarkSetupTreeTypes compares how each balancing
+// scheme handles the near-sorted updates of sweep lines.
+func BenchmarkSetupTreeTypes(b *testing.B) {
+	rand.Seed(seed)
+	dc := dcel.Random2DDCEL(inputRange, 100)
+	for i, typ := range treeTypes {
+		b.Run("Slab"+treeNames[i], func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				slab.Decompose(dc, typ)
+			}
+		})
+		b.Run("Monotone"+treeNames[i], func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				monotone.SplitWith(dc, typ)
+			}
+		})
 	}
 }
 
@@ -238,7 +287,7 @@ func BenchmarkRandomSetupTrapezoid(b *testing.B) {
 	rand.Seed(seed)
 	for i := 0; i < b.N; i++ {
 		dc := dcel.Random2DDCEL(inputRange, inputSize)
-		trapezoid.TrapezoidalMap(dc)
+		benchTrapezoid.TrapezoidalMap(dc)
 	}
 }
 
@@ -254,6 +303,18 @@ func BenchmarkRandomSetupRtree(b *testing.B) {
 	}
 }
 
+func BenchmarkRandomSetupKirkpatrick(b *testing.B) {
+	if seed == 0 {
+		fmt.Println("Setting seed")
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	for i := 0; i < b.N; i++ {
+		dc := dcel.Random2DDCEL(inputRange, inputSize)
+		kirkpatrick.TriangleTree(dc, kirkpatrick.MONOTONE)
+	}
+}
+
 func BenchmarkRandomSetupPlumbLine(b *testing.B) {
 	if seed == 0 {
 		fmt.Println("Setting seed")
@@ -262,7 +323,7 @@ func BenchmarkRandomSetupPlumbLine(b *testing.B) {
 	rand.Seed(seed)
 	for i := 0; i < b.N; i++ {
 		dc := dcel.Random2DDCEL(inputRange, inputSize)
-		bruteForce.PlumbLine(dc)
+		benchBruteForce.PlumbLine(dc)
 	}
 }
 
@@ -278,6 +339,8 @@ func BenchmarkAll(b *testing.B) {
 		b.Run("Trapezoid", BenchmarkRandomDCELTrapezoid)
 		b.Run("RtreeSetup", BenchmarkRandomSetupRtree)
 		b.Run("Rtree", BenchmarkRandomDCELRtree)
+		b.Run("KirkpatrickSetup", BenchmarkRandomSetupKirkpatrick)
+		b.Run("Kirkpatrick", BenchmarkRandomDCELKirkpatrick)
 		b.Run("PlumbLineSetup", BenchmarkRandomSetupPlumbLine)
 		b.Run("PlumbLine", BenchmarkRandomDCELPlumbLine)
 	}
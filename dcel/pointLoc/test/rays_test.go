@@ -0,0 +1,82 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// shootRay finds by brute force the edge first hit by a
+// ray shot from (x, y), upward if up is true and otherwise
+// downward.
+func shootRay(dc *dcel.DCEL, x, y float64, up bool) *dcel.Edge {
+	var best *dcel.Edge
+	bestDist := math.Inf(1)
+	for i := 0; i < len(dc.HalfEdges); i += 2 {
+		e := dc.HalfEdges[i]
+		a, b := e.Origin, e.Twin.Origin
+		if a.X() == b.X() || x < math.Min(a.X(), b.X()) || x > math.Max(a.X(), b.X()) {
+			continue
+		}
+		ey := a.Y() + (b.Y()-a.Y())*(x-a.X())/(b.X()-a.X())
+		d := ey - y
+		if !up {
+			d = -d
+		}
+		if d >= 0 && d < bestDist {
+			best = e
+			bestDist = d
+		}
+	}
+	return best
+}
+
+func testRayShooting(t *testing.T, dc *dcel.DCEL, sr pointLoc.ShootsRays) {
+	for i := 0; i < testCt/10; i++ {
+		pt := randomPt()
+		for _, up := range []bool{true, false} {
+			expected := shootRay(dc, pt.X(), pt.Y(), up)
+			var e *dcel.Edge
+			if up {
+				e = sr.EdgeAbove(pt.X(), pt.Y())
+			} else {
+				e = sr.EdgeBelow(pt.X(), pt.Y())
+			}
+			if expected == nil {
+				assert.Nil(t, e)
+				continue
+			}
+			if !assert.NotNil(t, e) {
+				continue
+			}
+			assert.True(t, e == expected || e.Twin == expected)
+			// The returned half edge faces the query point
+			if up {
+				assert.True(t, e.Origin.X() < e.Twin.Origin.X())
+			} else {
+				assert.True(t, e.Origin.X() > e.Twin.Origin.X())
+			}
+			if e.Face != dc.Faces[dcel.OUTER_FACE] {
+				assert.True(t, e.Face.Contains(pt))
+			}
+		}
+	}
+}
+
+func TestRandomDCELRayShooting(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+
+	sl, err := slab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	testRayShooting(t, dc, sl)
+
+	_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	testRayShooting(t, dc, tr)
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomLines returns a square of side size and n segments
+// running from one random point on its sides to another.
+func randomLines(rng *rand.Rand, size float64, n int) []geom.FullEdge {
+	corners := []geom.Point{
+		geom.NewPoint(0, 0, 0),
+		geom.NewPoint(size, 0, 0),
+		geom.NewPoint(size, size, 0),
+		geom.NewPoint(0, size, 0),
+	}
+	segs := []geom.FullEdge{}
+	for i, c := range corners {
+		segs = append(segs, geom.FullEdge{c, corners[(i+1)%4]})
+	}
+	side := func() geom.Point {
+		v := rng.Float64() * size
+		switch rng.Intn(4) {
+		case 0:
+			return geom.NewPoint(v, 0, 0)
+		case 1:
+			return geom.NewPoint(size, v, 0)
+		case 2:
+			return geom.NewPoint(v, size, 0)
+		}
+		return geom.NewPoint(0, v, 0)
+	}
+	for i := 0; i < n; i++ {
+		segs = append(segs, geom.FullEdge{side(), side()})
+	}
+	return segs
+}
+
+// testMainLocators checks that the slab decomposition and
+// trapezoidal map of dc agree with its plumb line at limit
+// random points.
+func testMainLocators(t *testing.T, dc *dcel.DCEL, limit int) {
+	locators := map[string]pointLoc.LocatesPoints{}
+	sl, err := slab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	locators["slab"] = sl
+	_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	locators["trapezoid"] = tr
+	pl := bruteForce.PlumbLine(dc)
+	for j := 0; j < limit; j++ {
+		pt := randomPt()
+		expected, err := pl.PointLocate(pt.X(), pt.Y())
+		assert.Nil(t, err)
+		for name, l := range locators {
+			f, err := l.PointLocate(pt.X(), pt.Y())
+			assert.Nil(t, err, name)
+			if !assert.Equal(t, dc.ScanFaces(expected), dc.ScanFaces(f), "%s at %v", name, pt) {
+				t.FailNow()
+			}
+		}
+	}
+}
+
+func TestLocateFromSegments(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		dc, err := dcel.FromSegments(randomLines(rng, inputRange, 10))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Empty(t, dc.Validate())
+		testMainLocators(t, dc, 1000)
+	}
+}
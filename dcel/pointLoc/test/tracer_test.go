@@ -0,0 +1,186 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/kirkpatrick"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/slab"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// countTracer counts the steps it is told of by name.
+type countTracer map[string]int
+
+func (ct countTracer) SweepLine(geom.D2)          { ct["SweepLine"]++ }
+func (ct countTracer) EdgeAdded(a, b geom.D2)     { ct["EdgeAdded"]++ }
+func (ct countTracer) EdgeRemoved(a, b geom.D2)   { ct["EdgeRemoved"]++ }
+func (ct countTracer) EdgeChecked(a, b geom.D2)   { ct["EdgeChecked"]++ }
+func (ct countTracer) FaceChecked(*dcel.Face)     { ct["FaceChecked"]++ }
+func (ct countTracer) TrapezoidCreated([]geom.D2) { ct["TrapezoidCreated"]++ }
+func (ct countTracer) RegionChecked([]geom.D2)    { ct["RegionChecked"]++ }
+
+// addedTracer records the edges it is told were added.
+type addedTracer struct {
+	pointLoc.NoTracer
+	added map[[4]float64]bool
+}
+
+func (at addedTracer) EdgeAdded(a, b geom.D2) {
+	at.added[[4]float64{a.X(), a.Y(), b.X(), b.Y()}] = true
+}
+
+func TestTracer(t *testing.T) {
+	dc := dcel.Random2DDCELWithSeed(inputRange, 10, 1)
+	type traced struct {
+		build func(pointLoc.Tracer) (pointLoc.LocatesPoints, error)
+		// built and queried are the steps
+		// expected while building and querying.
+		built, queried []string
+	}
+	tests := map[string]traced{
+		"slab": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				return slab.Decompose(dc, tree.RedBlack, tr)
+			},
+			[]string{"SweepLine", "EdgeAdded", "EdgeRemoved"},
+			[]string{"FaceChecked"},
+		},
+		"trapezoid": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				_, _, pl, err := trapezoid.TrapezoidalMap(dc, tr)
+				return pl, err
+			},
+			[]string{"EdgeAdded", "TrapezoidCreated"},
+			[]string{"RegionChecked"},
+		},
+		"plumb line": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				return bruteForce.PlumbLine(dc, tr), nil
+			},
+			nil,
+			[]string{"FaceChecked"},
+		},
+		"kirkpatrick monotone": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				return kirkpatrick.TriangleTree(dc, kirkpatrick.MONOTONE, tr)
+			},
+			[]string{"SweepLine", "EdgeAdded", "EdgeRemoved", "EdgeChecked"},
+			[]string{"RegionChecked"},
+		},
+		"kirkpatrick trapezoid": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				return kirkpatrick.TriangleTree(dc, kirkpatrick.TRAPEZOID, tr)
+			},
+			[]string{"EdgeAdded", "TrapezoidCreated"},
+			[]string{"RegionChecked"},
+		},
+		"rtree": {
+			func(tr pointLoc.Tracer) (pointLoc.LocatesPoints, error) {
+				return rtree.DCELtoRtree(dc, tr), nil
+			},
+			nil,
+			[]string{"FaceChecked"},
+		},
+	}
+	// Query inside every face, so that each
+	// locator has some face to check.
+	pts := []geom.D2{}
+	for _, f := range dc.Faces[1:] {
+		vs := f.Vertices()
+		pts = append(pts, geom.NewPoint(
+			(vs[0].X()+vs[1].X()+vs[2].X())/3,
+			(vs[0].Y()+vs[1].Y()+vs[2].Y())/3, 0))
+	}
+	for name, test := range tests {
+		ct := countTracer{}
+		pl, err := test.build(ct)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+		for _, step := range test.built {
+			assert.NotZero(t, ct[step], "%s did not trace %s while building", name, step)
+		}
+		for _, step := range test.queried {
+			ct[step] = 0
+		}
+		for _, p := range pts {
+			_, err := pl.PointLocate(p.X(), p.Y())
+			assert.Nil(t, err, name)
+		}
+		for _, step := range test.queried {
+			assert.NotZero(t, ct[step], "%s did not trace %s while querying", name, step)
+		}
+	}
+}
+
+func TestMonotoneTracer(t *testing.T) {
+	// notched has split and merge vertices, so splitting
+	// it adds diagonals, as does triangulating the pieces.
+	ct := countTracer{}
+	_, _, err := monotone.Split(dcel.Polygon(notched), ct)
+	assert.Nil(t, err)
+	for _, step := range []string{"SweepLine", "EdgeAdded", "EdgeRemoved", "EdgeChecked"} {
+		assert.NotZero(t, ct[step], "Split did not trace %s", step)
+	}
+	// Each corner is swept past once.
+	assert.Equal(t, len(notched), ct["SweepLine"])
+
+	// Every diagonal, bounded by the polygon's
+	// face on both sides, should be traced.
+	at := addedTracer{added: map[[4]float64]bool{}}
+	tri, _, err := monotone.Triangulate(dcel.Polygon(notched), at)
+	if !assert.Nil(t, err) {
+		return
+	}
+	outer := tri.Faces[dcel.OUTER_FACE]
+	diagonals := 0
+	for _, e := range tri.HalfEdges {
+		if e.Face == outer || e.Twin.Face == outer {
+			continue
+		}
+		diagonals++
+		a, b := e.Origin, e.Twin.Origin
+		assert.True(t, at.added[[4]float64{a.X(), a.Y(), b.X(), b.Y()}] ||
+			at.added[[4]float64{b.X(), b.Y(), a.X(), a.Y()}], "%v to %v", a, b)
+	}
+	assert.Equal(t, len(notched)-3, diagonals/2)
+}
+
+func TestSlabQuiet(t *testing.T) {
+	dc := dcel.Random2DDCELWithSeed(inputRange, 10, 1)
+	r, w, err := os.Pipe()
+	if !assert.Nil(t, err) {
+		return
+	}
+	// The pipe is drained as it is written to,
+	// so that printing cannot block.
+	outCh := make(chan []byte)
+	go func() {
+		out, _ := ioutil.ReadAll(r)
+		outCh <- out
+	}()
+	stdout := os.Stdout
+	os.Stdout = w
+	pl, err := slab.Decompose(dc, tree.RedBlack)
+	if err == nil {
+		for i := 0; i < 100; i++ {
+			p := randomPt()
+			pl.PointLocate(p.X(), p.Y())
+		}
+	}
+	os.Stdout = stdout
+	w.Close()
+	out := <-outCh
+	assert.Nil(t, err)
+	assert.Empty(t, string(out))
+}
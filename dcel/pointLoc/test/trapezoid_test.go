@@ -0,0 +1,87 @@
+package test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/nylen/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrapezoidDCEL(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		dc := dcel.Random2DDCELWithSeed(inputRange, 10, int64(i))
+		tdc, mp, _, err := trapezoid.TrapezoidalMapWithRand(dc, rand.New(rand.NewSource(int64(i))))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Empty(t, tdc.Validate(), "seed %d", i)
+		// Every trapezoid should lie in the face it maps to,
+		// and together those within the dcel should cover it
+		// once, no trapezoid being listed twice. Trapezoids
+		// are convex, so the mean of their vertices, which
+		// include the corners of their neighbors along their
+		// walls, lies within them.
+		pl := bruteForce.PlumbLine(dc)
+		covered := 0.0
+		for _, f := range tdc.Faces[dcel.OUTER_FACE+1:] {
+			vs := f.Vertices()
+			c := geom.NewPoint(0, 0, 0)
+			for _, v := range vs {
+				c[0] += v.X() / float64(len(vs))
+				c[1] += v.Y() / float64(len(vs))
+			}
+			want, err := pl.PointLocate(c.X(), c.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, want, mp[f], "seed %d at %v", i, c)
+			if mp[f] != dc.Faces[dcel.OUTER_FACE] {
+				covered += faceArea(f)
+			}
+		}
+		area := 0.0
+		for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+			area += faceArea(f)
+		}
+		assert.InDelta(t, area, covered, area*1e-9, "seed %d", i)
+	}
+}
+
+func TestTrapezoidFan(t *testing.T) {
+	// Every spoke of this fan shares its hub, and one
+	// is vertical, so queries along spokes start on
+	// the edges they are compared against.
+	hub := geom.NewPoint(500, 500, 0)
+	segs := []geom.FullEdge{}
+	n := 12
+	rim := make([]geom.Point, n)
+	for i := range rim {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		rim[i] = geom.NewPoint(500+400*math.Cos(a), 500+400*math.Sin(a), 0)
+	}
+	rim[3][0] = hub[0]
+	for i, p := range rim {
+		segs = append(segs, geom.FullEdge{hub, p}, geom.FullEdge{p, rim[(i+1)%n]})
+	}
+	dc, err := dcel.FromSegments(segs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, _, tr, err := trapezoid.TrapezoidalMapWithRand(dc, rand.New(rand.NewSource(1)))
+	if !assert.Nil(t, err) {
+		return
+	}
+	pl := bruteForce.PlumbLine(dc)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		want, err := pl.PointLocate(x, y)
+		assert.Nil(t, err)
+		got, err := tr.PointLocate(x, y)
+		assert.Nil(t, err)
+		assert.Equal(t, want, got, "at %v, %v", x, y)
+	}
+}
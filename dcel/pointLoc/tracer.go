@@ -0,0 +1,72 @@
+package pointLoc
+
+import (
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// A Tracer is told of the steps point locators take as they
+// are built and queried, so that those steps can be drawn or
+// logged. Builders take a Tracer as an optional last argument,
+// and the locators they return trace their queries to it as
+// well. As locators may be queried concurrently, a Tracer may
+// be called from many goroutines at once.
+type Tracer interface {
+	// SweepLine is called with a point on each line swept to
+	// or compared against. These lines are vertical, save for
+	// those of the top to bottom sweeps in monotone.
+	SweepLine(p geom.D2)
+	// EdgeAdded is called when the edge from a to b
+	// is added to a search structure.
+	EdgeAdded(a, b geom.D2)
+	// EdgeRemoved is called when the edge from a to b
+	// is removed from a search structure.
+	EdgeRemoved(a, b geom.D2)
+	// EdgeChecked is called when the edge from a to b
+	// is compared against.
+	EdgeChecked(a, b geom.D2)
+	// FaceChecked is called when f is checked
+	// for whether it contains a query point.
+	FaceChecked(f *dcel.Face)
+	// TrapezoidCreated is called with the
+	// corners of each trapezoid created.
+	TrapezoidCreated(corners []geom.D2)
+	// RegionChecked is called with the corners of each convex
+	// region, such as a trapezoid or a triangle, which a query
+	// passes through.
+	RegionChecked(corners []geom.D2)
+}
+
+// NoTracer is a Tracer which ignores every step.
+type NoTracer struct{}
+
+// SweepLine does nothing.
+func (NoTracer) SweepLine(geom.D2) {}
+
+// EdgeAdded does nothing.
+func (NoTracer) EdgeAdded(a, b geom.D2) {}
+
+// EdgeRemoved does nothing.
+func (NoTracer) EdgeRemoved(a, b geom.D2) {}
+
+// EdgeChecked does nothing.
+func (NoTracer) EdgeChecked(a, b geom.D2) {}
+
+// FaceChecked does nothing.
+func (NoTracer) FaceChecked(*dcel.Face) {}
+
+// TrapezoidCreated does nothing.
+func (NoTracer) TrapezoidCreated([]geom.D2) {}
+
+// RegionChecked does nothing.
+func (NoTracer) RegionChecked([]geom.D2) {}
+
+// OptionalTracer returns the first of trs, or a NoTracer if trs
+// is empty or its first Tracer is nil. Builders pass the optional
+// Tracer they are given through it.
+func OptionalTracer(trs []Tracer) Tracer {
+	if len(trs) == 0 || trs[0] == nil {
+		return NoTracer{}
+	}
+	return trs[0]
+}
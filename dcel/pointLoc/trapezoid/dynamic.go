@@ -0,0 +1,351 @@
+package trapezoid
+
+import (
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+)
+
+// InsertEdge adds the segment fe, which lies between the given faces,
+// to the map rooted at tn, which should be the root returned by
+// TrapezoidalMap. fe must lie within the bounds of the map, and may
+// only meet segments already in the map at their endpoints. As fe
+// has no half edge of its own, ray shooting queries which hit fe
+// return nil. As adding an edge to a dcel can split a face in two,
+// every trapezoid in the region on each side of fe becomes part
+// of whichever of faces lies on that side, undoing DeleteEdge. If
+// both faces are nil, the faces of the map are left as they are.
+func (tn *Node) InsertEdge(fe geom.FullEdge, faces [2]*dcel.Face) error {
+	fe = ordered(fe)
+	lp, rp := fe[0], fe[1]
+	if lp == rp {
+		return compgeo.BadEdgeError{}
+	}
+	if !tn.inBounds(lp) || !tn.inBounds(rp) {
+		return compgeo.RangeError{}
+	}
+	trs := tn.Query(fe)
+	for i, tr := range trs {
+		if touches(fe, tr.topSeg) || touches(fe, tr.botSeg) {
+			return compgeo.BadEdgeError{}
+		}
+		// fe may not pass through the point defining
+		// a wall it crosses.
+		if i != len(trs)-1 && geom.Orient2D(lp, rp, tr.rightPt) == 0 {
+			return compgeo.BadEdgeError{}
+		}
+	}
+	insert(trs, fe, faces, nil, tn.trace())
+	if faces[0] == nil && faces[1] == nil {
+		return nil
+	}
+	outerFace := tn.payload.(*dcel.Face)
+	for _, below := range []bool{false, true} {
+		side := tn.along(fe, below)
+		if len(side) == 0 {
+			continue
+		}
+		// The trapezoids beside fe have no width if fe is
+		// vertical, so the face is found from the widest
+		// trapezoid of the region, whose middle is well
+		// inside it.
+		trs := region(side)
+		widest := trs[0]
+		for _, t := range trs {
+			if t.right-t.left > widest.right-widest.left {
+				widest = t
+			}
+		}
+		face := widest.faceOf(faces, outerFace)
+		for _, t := range trs {
+			t.faces = [2]*dcel.Face{face, nil}
+		}
+	}
+	return nil
+}
+
+// inBounds reports whether p lies within the trapezoid
+// it is located to, and so within the bounds of the map.
+func (tn *Node) inBounds(p geom.Point) bool {
+	trs := tn.Query(geom.FullEdge{p, p})
+	if len(trs) == 0 {
+		return false
+	}
+	tr := trs[0]
+	return !lexLess(p, tr.leftPt) && !lexLess(tr.rightPt, p) &&
+		!isAbove(p, tr.topSeg) &&
+		geom.Orient2D(tr.botSeg[0], tr.botSeg[1], p) >= 0
+}
+
+// DeleteEdge removes the segment fe from the map rooted at tn,
+// which should be the root returned by TrapezoidalMap. The
+// trapezoids on either side of fe are merged, and face becomes
+// the face which every trapezoid in the region around them lies
+// in, as removing an edge from a dcel merges the faces on either
+// side of it. face should be that merged face, so that it can be
+// checked for containing the points queried in it.
+func (tn *Node) DeleteEdge(fe geom.FullEdge, face *dcel.Face) error {
+	fe = ordered(fe)
+	lp, rp := fe[0], fe[1]
+	ups := tn.along(fe, false)
+	downs := tn.along(fe, true)
+	if ups == nil || downs == nil {
+		return compgeo.BadEdgeError{}
+	}
+	removed := append(append([]*Trapezoid{}, ups...), downs...)
+
+	// The wall through an endpoint of fe is removed along with
+	// fe, unless the point is an endpoint of another segment.
+	// If the wall goes, the trapezoid on its far side is merged
+	// in as well.
+	var lt, rt *Trapezoid
+	if n := ups[0].Neighbors[upleft]; n != nil && n == downs[0].Neighbors[botleft] {
+		lt = n
+		removed = append(removed, lt)
+	}
+	if n := ups[len(ups)-1].Neighbors[upright]; n != nil && n == downs[len(downs)-1].Neighbors[botright] {
+		rt = n
+		removed = append(removed, rt)
+	}
+
+	// Every wall which ended on fe now extends through to the
+	// other side of fe. From left to right, each pair of walls
+	// bounds one new trapezoid, under the top of the trapezoid
+	// above fe and over the bottom of the trapezoid below fe
+	// between them.
+	created := []*Trapezoid{}
+	upCover := make([][]*Trapezoid, len(ups))
+	downCover := make([][]*Trapezoid, len(downs))
+	start := lp
+	if lt != nil {
+		start = lt.leftPt
+	}
+	ui, di := 0, 0
+	for {
+		u, d := ups[ui], downs[di]
+		lastUp := ui == len(ups)-1
+		lastDown := di == len(downs)-1
+		var end geom.Point
+		switch {
+		case lastUp && lastDown:
+			end = rp
+			if rt != nil {
+				end = rt.rightPt
+			}
+		case lastDown || (!lastUp && lexLess(u.rightPt, d.rightPt)):
+			end = u.rightPt
+		default:
+			end = d.rightPt
+		}
+		t := new(Trapezoid)
+		t.topSeg = u.topSeg
+		t.botSeg = d.botSeg
+		t.topEdge = u.topEdge
+		t.botEdge = d.botEdge
+		t.leftPt = start
+		t.rightPt = end
+		t.setBounds()
+		NewTrapNode(t)
+		created = append(created, t)
+		upCover[ui] = append(upCover[ui], t)
+		downCover[di] = append(downCover[di], t)
+		if lastUp && lastDown {
+			break
+		}
+		if end == u.rightPt {
+			ui++
+		} else {
+			di++
+		}
+		start = end
+	}
+
+	// Each removed trapezoid's leaf is replaced by a tree
+	// picking between the new trapezoids which cover it.
+	// The query structure above those leaves still divides
+	// the plane correctly, as each side of any node leads
+	// to trapezoids which cover the old ones.
+	for i, u := range ups {
+		u.node.discard(xTree(upCover[i]))
+	}
+	for i, d := range downs {
+		d.node.discard(xTree(downCover[i]))
+	}
+	if lt != nil {
+		lt.node.discard(created[0].node)
+	}
+	if rt != nil {
+		rt.node.discard(created[len(created)-1].node)
+	}
+	linkNeighbors(removed, created)
+	relabel(created, face)
+	// Nodes which now lead to the same place on both
+	// sides are spliced out of the structure.
+	for _, t := range created {
+		for _, p := range append([]*Node{}, t.node.parents...) {
+			p.splice()
+		}
+	}
+	for _, t := range created {
+		tn.trace().TrapezoidCreated(t.AsPoints())
+	}
+	return nil
+}
+
+// relabel sets face as the face of every trapezoid
+// in the region trs lie in.
+func relabel(trs []*Trapezoid, face *dcel.Face) {
+	for _, t := range region(trs) {
+		t.faces = [2]*dcel.Face{face, nil}
+	}
+}
+
+// region returns trs and every trapezoid reachable from them
+// through their neighbors. No segment lies along the wall
+// between two neighbors, so those are the trapezoids of the
+// region trs lie in, up to the segments around it.
+func region(trs []*Trapezoid) []*Trapezoid {
+	seen := make(map[*Trapezoid]bool)
+	reached := []*Trapezoid{}
+	stack := append([]*Trapezoid{}, trs...)
+	for len(stack) != 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		reached = append(reached, t)
+		for _, n := range t.Neighbors {
+			if n != nil && !seen[n] {
+				stack = append(stack, n)
+			}
+		}
+	}
+	return reached
+}
+
+// along returns the trapezoids which lie directly below the ordered
+// segment fe if below is true, and directly above it otherwise,
+// from left to right. If fe is not in the map, along returns nil.
+func (tn *Node) along(fe geom.FullEdge, below bool) []*Trapezoid {
+	tr := tn.beside(fe, below)
+	trs := []*Trapezoid{}
+	for {
+		if tr == nil {
+			return nil
+		}
+		if below && tr.topSeg != fe || !below && tr.botSeg != fe {
+			return nil
+		}
+		trs = append(trs, tr)
+		if tr.rightPt == fe[1] {
+			return trs
+		}
+		if below {
+			tr = tr.Neighbors[upright]
+		} else {
+			tr = tr.Neighbors[botright]
+		}
+	}
+}
+
+// beside returns the trapezoid just right of the left point of
+// the ordered segment fe, below fe if below is true and above it
+// otherwise. It descends as a query on fe would, but does not
+// assume fe is above anything it lies on.
+func (tn *Node) beside(fe geom.FullEdge, below bool) *Trapezoid {
+	n := tn
+	for n != nil {
+		switch v := n.payload.(type) {
+		case *Trapezoid:
+			return v
+		case geom.Point:
+			if lexLess(fe[0], v) {
+				n = n.left
+			} else {
+				n = n.right
+			}
+		case geom.FullEdge:
+			cp := geom.Orient2D(v[0], v[1], fe[0])
+			if cp == 0 {
+				cp = geom.Orient2D(v[0], v[1], fe[1])
+			}
+			if v == fe {
+				cp = 1
+				if below {
+					cp = -1
+				}
+			}
+			if cp >= 0 {
+				n = n.left
+			} else {
+				n = n.right
+			}
+		default:
+			n = n.left
+		}
+	}
+	return nil
+}
+
+// xTree returns a tree of X-Nodes picking between trs, which
+// must be ordered from left to right, each sharing its right
+// wall with the next.
+func xTree(trs []*Trapezoid) *Node {
+	if len(trs) == 1 {
+		return trs[0].node
+	}
+	mid := len(trs) / 2
+	n := NewX(trs[mid].leftPt)
+	n.set(left, xTree(trs[:mid]))
+	n.set(right, xTree(trs[mid:]))
+	return n
+}
+
+// splice removes tn from the query structure if both of its
+// children are the same node, then does the same for its parents.
+func (tn *Node) splice() {
+	c := tn.left
+	if c == nil || c != tn.right {
+		return
+	}
+	ps := []*Node{}
+	for _, p := range c.parents {
+		if p != tn {
+			ps = append(ps, p)
+		}
+	}
+	c.parents = ps
+	tn.discard(c)
+	// A parent may be visited twice, if both of its
+	// children were tn, so tn is cleared to mark it as gone.
+	ps = tn.parents
+	tn.left, tn.right, tn.parents = nil, nil, nil
+	for _, p := range ps {
+		p.splice()
+	}
+}
+
+// touches reports whether the ordered segments a and b
+// share any point other than a common endpoint.
+func touches(a, b geom.FullEdge) bool {
+	if a == b {
+		return true
+	}
+	d1 := geom.Orient2D(a[0], a[1], b[0])
+	d2 := geom.Orient2D(a[0], a[1], b[1])
+	d3 := geom.Orient2D(b[0], b[1], a[0])
+	d4 := geom.Orient2D(b[0], b[1], a[1])
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return within(a, b[0], d1) || within(a, b[1], d2) ||
+		within(b, a[0], d3) || within(b, a[1], d4)
+}
+
+// within reports whether p lies strictly inside the ordered
+// segment fe, given the cross product of fe and p.
+func within(fe geom.FullEdge, p geom.Point, cp float64) bool {
+	return cp == 0 && lexLess(fe[0], p) && lexLess(p, fe[1])
+}
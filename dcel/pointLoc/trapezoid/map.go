@@ -4,29 +4,48 @@ import (
 	"math/rand"
 
 	"github.com/nylen/go-compgeo/dcel"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 )
 
-var (
-	tree *Node
-	err  error
-)
-
 // TrapezoidalMap converts a dcel into a version of itself split into
 // trapezoids and a search structure to find a containing trapezoid in
-// the map in response to a point location query.
-func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
+// the map in response to a point location query. If a Tracer is
+// given, the map's construction and the queries on the returned
+// structure are traced to it.
+func TrapezoidalMap(dc *dcel.DCEL, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
+	return TrapezoidalMapWithRand(dc, nil, tr...)
+}
+
+// TrapezoidalMapWithRand acts as TrapezoidalMap, shuffling the edges
+// of dc with rnd, so that maps built from the same dcel with equally
+// seeded rnds are identical. If rnd is nil, the global source of
+// math/rand is used. All state used to build the map is owned by this
+// call, so separate maps may be built concurrently, so long as they
+// do not share rnd.
+func TrapezoidalMapWithRand(dc *dcel.DCEL, rnd *rand.Rand, tr ...pointLoc.Tracer) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
+	// The map's bounds are kept clear of the dcel, so that
+	// no edge lies along the top or bottom of the map.
 	bounds := dc.Bounds()
+	min := bounds.At(geom.SPAN_MIN).(geom.Point)
+	max := bounds.At(geom.SPAN_MAX).(geom.Point)
+	bounds = bounds.Expand(
+		geom.NewPoint(min.X()-1, min.Y()-1, 0),
+		geom.NewPoint(max.X()+1, max.Y()+1, 0))
 
-	tree = NewRoot()
+	tree := NewRoot()
 	tree.payload = dc.Faces[dcel.OUTER_FACE]
+	tree.tracer = pointLoc.OptionalTracer(tr)
 	tree.set(left, NewTrapNode(newTrapezoid(bounds)))
 
 	fullEdges, faces, err := dc.FullEdges()
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	edges := make([]*dcel.Edge, len(fullEdges))
+	for i := range edges {
+		edges[i] = rightward(dc.HalfEdges[2*i])
+	}
 	// Get rid of bad (duplicate) edges
 	i := 0
 	for i < len(fullEdges) {
@@ -36,36 +55,51 @@ func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node
 		if geom.F64eq(l.X(), r.X()) && geom.F64eq(l.Y(), r.Y()) {
 			fullEdges = append(fullEdges[0:i], fullEdges[i+1:]...)
 			faces = append(faces[0:i], faces[i+1:]...)
+			edges = append(edges[0:i], edges[i+1:]...)
 			i--
 		}
 		i++
 	}
 	// Scramble the edges
+	intn := rand.Intn
+	if rnd != nil {
+		intn = rnd.Intn
+	}
 	for i := range fullEdges {
-		j := i + rand.Intn(len(fullEdges)-i)
+		j := i + intn(len(fullEdges)-i)
 		fullEdges[i], fullEdges[j] = fullEdges[j], fullEdges[i]
 		faces[i], faces[j] = faces[j], faces[i]
+		edges[i], edges[j] = edges[j], edges[i]
 	}
 	for k, fe := range fullEdges {
-		visualize.HighlightColor = visualize.AddColor
-		visualize.DrawLine(fe.Left(), fe.Right())
-		// 1: Find the trapezoids intersected by fe
-		trs := tree.Query(fe)
-		// 2: Remove those and replace them with what they become
-		//    due to the intersection of halfEdges[i]
-		// Case A: A fe is contained in a single trapezoid tr
-		// Then we make (up to) four trapezoids out of tr.
-		if len(trs) == 0 {
-			continue
-		}
-		if len(trs) == 1 {
-			visualize.HighlightColor = visualize.CheckFaceColor
-			visualize.DrawPoly(trs[0].toPhysics())
-			mapSingleCase(trs[0], fe, faces[k])
-		} else {
-			mapMultipleCase(trs, fe, faces[k])
-		}
+		fe = ordered(fe)
+		insert(tree.Query(fe), fe, faces[k], edges[k], tree.tracer)
 	}
 	dc, m := tree.DCEL()
 	return dc, m, tree, nil
 }
+
+// insert adds the ordered segment fe to the map, given the
+// trapezoids which fe passes through. e is the rightward half
+// edge along fe, if there is one.
+func insert(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face, e *dcel.Edge, tracer pointLoc.Tracer) {
+	tracer.EdgeAdded(fe[0], fe[1])
+	// Remove the trapezoids fe passes through and replace
+	// them with what they become due to the intersection
+	// of fe, and update the query structure to match.
+	var created []*Trapezoid
+	switch len(trs) {
+	case 0:
+		return
+	case 1:
+		// Case A: fe is contained in a single trapezoid tr
+		// Then we make (up to) four trapezoids out of tr.
+		created = mapSingleCase(trs[0], fe, faces, e)
+	default:
+		// Case B: fe crosses more than one trapezoid
+		created = mapMultipleCase(trs, fe, faces, e)
+	}
+	for _, tr := range created {
+		tracer.TrapezoidCreated(tr.AsPoints())
+	}
+}
@@ -5,80 +5,56 @@ import (
 	"github.com/nylen/go-compgeo/geom"
 )
 
-func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
-
-	var l, r *Trapezoid
-	ur, br, ul, bl := tr.GetNeighbors()
-	lp, rp := fe.BothPoints()
-
-	u := tr.Copy()
-	d := tr.Copy()
-
+// mapSingleCase splits tr, which wholly contains fe, into
+// up to four trapezoids: one left of fe, one right of fe,
+// and one each above and below fe. fe must be ordered,
+// and e must be the rightward half edge along fe, or nil if
+// fe was not inserted from a dcel.
+func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face, e *dcel.Edge) []*Trapezoid {
+	lp, rp := fe[0], fe[1]
+
+	u := tr.piece(lp, rp)
+	u.botSeg = fe
+	u.botEdge = twin(e)
 	u.faces = faces
-	d.faces = faces
-
-	// LP does not lie on the left edge of TR
-	if !geom.F64eq(lp.X(), tr.left) {
-		l = tr.Copy()
-		NewTopRight, _ := l.TopEdge().PointAt(0, lp.X())
-		NewBotRight, _ := l.BotEdge().PointAt(0, lp.X())
-		l.right = lp.X()
-		l.bot[right] = NewBotRight.Y()
-		l.top[right] = NewTopRight.Y()
-		d.bot[left] = NewBotRight.Y()
-		u.top[left] = NewTopRight.Y()
-		ul.replaceNeighbors(tr, l)
-		bl.replaceNeighbors(tr, l)
+	u.setBounds()
 
-		l.twoRights(u, d, lp.Y())
-	} else {
-		tr.replaceLeftPointers(u, d, lp.Y())
-	}
-	if !geom.F64eq(rp.X(), tr.right) {
-		r = tr.Copy()
-		NewTopLeft, _ := r.TopEdge().PointAt(0, rp.X())
-		NewBotLeft, _ := r.BotEdge().PointAt(0, rp.X())
-		r.left = rp.X()
-		r.bot[left] = NewBotLeft.Y()
-		r.top[left] = NewTopLeft.Y()
-		d.bot[right] = NewBotLeft.Y()
-		u.top[right] = NewTopLeft.Y()
-		ur.replaceNeighbors(tr, r)
-		br.replaceNeighbors(tr, r)
-
-		r.twoLefts(u, d, rp.Y())
-	} else {
-		tr.replaceRightPointers(u, d, rp.Y())
+	d := tr.piece(lp, rp)
+	d.topSeg = fe
+	d.topEdge = e
+	d.faces = faces
+	d.setBounds()
+
+	created := []*Trapezoid{u, d}
+
+	// From the query structure, remove the leaf of tr
+	// and replace it with a tree which picks between
+	// the new trapezoids.
+	var n *Node
+	n = NewY(fe)
+	n.set(left, NewTrapNode(u))
+	n.set(right, NewTrapNode(d))
+
+	// The wall through rp is new, unless rp
+	// already defined tr's right wall.
+	if rp != tr.rightPt {
+		r := tr.piece(rp, tr.rightPt)
+		created = append(created, r)
+		b := NewX(rp)
+		b.set(left, n)
+		b.set(right, NewTrapNode(r))
+		n = b
 	}
-
-	// D and U are exactly below // above
-	// the input edge.
-	splitExactly(u, d, fe)
-
-	// 3: From the query structure, remove the leaves of the
-	//    removed trapezoids and add new leaves for the new
-	//    trapezoids, with additional inner nodes as necessary.
-
-	a := NewX(lp)
-	b := NewX(rp)
-	c := NewY(fe)
-
-	// Our structure should have tr's parent point to a,
-	// a point to l and b, b point to r and c, and c
-	// point to u and d
-
-	tr.node.discard(a)
-
-	if l != nil {
+	if lp != tr.leftPt {
+		l := tr.piece(tr.leftPt, lp)
+		created = append(created, l)
+		a := NewX(lp)
 		a.set(left, NewTrapNode(l))
+		a.set(right, n)
+		n = a
 	}
-	a.set(right, b)
-	b.set(left, c)
-	if r != nil {
-		b.set(right, NewTrapNode(r))
-	}
-	c.set(left, NewTrapNode(u))
-	c.set(right, NewTrapNode(d))
+	tr.node.discard(n)
 
-	annotatedVisualize([]string{"L", "R", "U", "D"}, []*Trapezoid{l, r, u, d})
+	linkNeighbors([]*Trapezoid{tr}, created)
+	return created
 }
@@ -1,75 +1,256 @@
 package trapezoid
 
 import (
-	"fmt"
+	"math"
+	"sort"
 
 	compgeo "github.com/nylen/go-compgeo"
 	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/printutil"
 )
 
+// ε is the distance within which trapezoid corners
+// are considered to be the same point.
+const ε = 1.0e-7
+
 // A Node is a node in a tree structure
 // for trapezoid map queries. It is structured so that
 // each variety of Node is the same struct, but
 // each has a different payload and query function.
+// Queries do not modify the structure, so once
+// TrapezoidalMap returns it may be queried concurrently.
+// The root node holds the Tracer which the map's
+// construction and queries are traced to.
 type Node struct {
 	left, right *Node
 	parents     []*Node
-	query       func(geom.FullEdge, *Node) []*Trapezoid
+	query       func(geom.FullEdge, *Node, pointLoc.Tracer) []*Trapezoid
 	payload     interface{}
+	tracer      pointLoc.Tracer
 }
 
 // DCEL converts the trapezoids in the node search structure
-// into a DCEL.
-// This algorithm does not work?
-// Consider scrapping this and replacing it with encoding the
-// trapezoids as an OFF structure and decoding the resulting
-// OFF structure as a DCEL.
+// into a DCEL, where each trapezoid is a face. Where a trapezoid's
+// walls meet the top or bottom of a neighboring trapezoid, that
+// neighbor gains a vertex, so the trapezoids share whole edges.
+// The returned map takes faces in the output DCEL to the
+// faces of the DCEL the map was built from which they are in.
 func (tn *Node) DCEL() (*dcel.DCEL, map[*dcel.Face]*dcel.Face) {
 	dc := new(dcel.DCEL)
-	trs := tn.inOrder()
+	var outerFace *dcel.Face
+	if f, ok := tn.payload.(*dcel.Face); ok {
+		outerFace = f
+	}
+	trs := []*Trapezoid{}
+	for _, tr := range tn.inOrder() {
+		if tr.right-tr.left > ε &&
+			(tr.top[left]-tr.bot[left] > ε || tr.top[right]-tr.bot[right] > ε) {
+			trs = append(trs, tr)
+		}
+	}
+	vs := newVertexSet(trs)
+	dc.Vertices = vs.vs
+
 	// This maps from faces in the output of this algorithm
 	// to faces in the input of the TrapezoidMap.
 	fMap := make(map[*dcel.Face]*dcel.Face)
-	vMap := make(map[geom.Point]*dcel.Edge)
-	dc.Faces = make([]*dcel.Face, len(trs))
-	// Todo: benchmark if it is faster to initially set this
-	// at len(trs) because we know that's a minimum,
-	// and then make if checks down the line for whether we append
-	// or set.
-	dc.Vertices = make([]*dcel.Vertex, 0)
+	dc.Faces = make([]*dcel.Face, len(trs)+1)
+	dc.Faces[dcel.OUTER_FACE] = dcel.NewFace()
+	fMap[dc.Faces[dcel.OUTER_FACE]] = outerFace
+
+	type vPair [2]*dcel.Vertex
+	edgeMap := make(map[vPair]*dcel.Edge)
+	inner := []*dcel.Edge{}
 	for i, tr := range trs {
-		// Each trapezoid becomes a face
-		dc.Faces[i] = dcel.NewFace()
-		fMap[dc.Faces[i]] = tr.faces[0]
-		// each of the up to four edges in a trapezoid
-		// has an edge associated with it, the first of which is the face's
-		// inner (going with the broken convention of always using inner)
-		edges := tr.DCELEdges()
-		dc.Faces[i].Outer = edges[0]
-		dc.HalfEdges = append(dc.HalfEdges, edges...)
-		// each vertex in each trapezoid, if it has not been seen before,
-		// is added to the dcel and added to a map connected to it's edge
-		// but if it is in the map,
-		// it defines that the edge it is mapped to is the twin of the
-		// current edge's previous, and vice versa.
-		for _, e := range edges {
-			e.Face = dc.Faces[i]
-			if e2, ok := vMap[e.Origin.Point]; ok {
-				e.SetTwin(e2.Prev)
-				delete(vMap, e.Origin.Point)
-			} else {
-				vMap[e.Origin.Point] = e
-				dc.Vertices = append(dc.Vertices, e.Origin)
-			}
+		f := dcel.NewFace()
+		dc.Faces[i+1] = f
+		fMap[f] = tr.inputFace(outerFace)
+		poly := vs.boundary(tr)
+		edges := make([]*dcel.Edge, len(poly))
+		for j, v := range poly {
+			edges[j] = dcel.NewEdge()
+			edges[j].Origin = v
+			edges[j].Face = f
+			v.OutEdge = edges[j]
+			edgeMap[vPair{v, poly[(j+1)%len(poly)]}] = edges[j]
+		}
+		for j, e := range edges {
+			e.SetNext(edges[(j+1)%len(edges)])
+		}
+		f.Outer = edges[0]
+		inner = append(inner, edges...)
+	}
+	// Twin each edge, giving edges without a twin on
+	// the bounds of the map a twin on the outer face.
+	outerFrom := make(map[*dcel.Vertex]*dcel.Edge)
+	outer := []*dcel.Edge{}
+	for _, e := range inner {
+		if e.Twin != nil {
+			continue
+		}
+		next := e.Next.Origin
+		t, ok := edgeMap[vPair{next, e.Origin}]
+		if !ok {
+			t = dcel.NewEdge()
+			t.Origin = next
+			t.Face = dc.Faces[dcel.OUTER_FACE]
+			outerFrom[next] = t
+			outer = append(outer, t)
 		}
+		e.SetTwin(t)
+		dc.HalfEdges = append(dc.HalfEdges, e, t)
+	}
+	for _, t := range outer {
+		if n, ok := outerFrom[t.Twin.Origin]; ok {
+			t.SetNext(n)
+		}
+	}
+	if len(outer) != 0 {
+		dc.Faces[dcel.OUTER_FACE].Inner = []*dcel.Edge{outer[0]}
 	}
-	//dc.CorrectTwins()
 	return dc, fMap
 }
 
+// inputFace returns which of tr's faces tr lies in,
+// or outerFace if tr lies in neither.
+func (tr *Trapezoid) inputFace(outerFace *dcel.Face) *dcel.Face {
+	return tr.faceOf(tr.faces, outerFace)
+}
+
+// faceOf returns which of faces tr lies in,
+// or outerFace if tr lies in neither.
+func (tr *Trapezoid) faceOf(faces [2]*dcel.Face, outerFace *dcel.Face) *dcel.Face {
+	c := geom.NewPoint(
+		(tr.left+tr.right)/2,
+		(tr.top[left]+tr.top[right]+tr.bot[left]+tr.bot[right])/4,
+		0)
+	for _, f := range faces {
+		if f != nil && f != outerFace && f.Contains(c) {
+			return f
+		}
+	}
+	return outerFace
+}
+
+// A vertexSet holds the distinct corners of a set of trapezoids,
+// sorted by x and then y, merging corners within ε of each other.
+type vertexSet struct {
+	vs []*dcel.Vertex
+}
+
+func newVertexSet(trs []*Trapezoid) vertexSet {
+	pts := make([]geom.D2, 0, len(trs)*4)
+	for _, tr := range trs {
+		pts = append(pts, tr.AsPoints()...)
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].X() != pts[j].X() {
+			return pts[i].X() < pts[j].X()
+		}
+		return pts[i].Y() < pts[j].Y()
+	})
+	vs := vertexSet{}
+	for _, p := range pts {
+		if vs.find(p) == nil {
+			vs.vs = append(vs.vs, dcel.NewVertex(p.X(), p.Y(), 0))
+			// Keep vs sorted, as merging can leave a point
+			// out of order with its predecessor by up to ε.
+			for i := len(vs.vs) - 1; i > 0 && vs.less(vs.vs[i], vs.vs[i-1]); i-- {
+				vs.vs[i], vs.vs[i-1] = vs.vs[i-1], vs.vs[i]
+			}
+		}
+	}
+	return vs
+}
+
+func (vs vertexSet) less(a, b geom.D2) bool {
+	if a.X() != b.X() {
+		return a.X() < b.X()
+	}
+	return a.Y() < b.Y()
+}
+
+// within returns the vertices whose x values are within
+// [x1 - ε, x2 + ε], in sorted order.
+func (vs vertexSet) within(x1, x2 float64) []*dcel.Vertex {
+	i := sort.Search(len(vs.vs), func(i int) bool {
+		return vs.vs[i].X() >= x1-ε
+	})
+	j := sort.Search(len(vs.vs), func(i int) bool {
+		return vs.vs[i].X() > x2+ε
+	})
+	return vs.vs[i:j]
+}
+
+func (vs vertexSet) find(p geom.D2) *dcel.Vertex {
+	for _, v := range vs.within(p.X(), p.X()) {
+		if math.Abs(v.Y()-p.Y()) <= ε {
+			return v
+		}
+	}
+	return nil
+}
+
+// boundary returns the vertices around tr, clockwise
+// from its top left corner.
+func (vs vertexSet) boundary(tr *Trapezoid) []*dcel.Vertex {
+	pts := tr.AsPoints()
+	poly := []*dcel.Vertex{}
+	add := func(v *dcel.Vertex) {
+		if len(poly) == 0 || (poly[len(poly)-1] != v && poly[0] != v) {
+			poly = append(poly, v)
+		}
+	}
+	onLine := func(v *dcel.Vertex, a, b geom.D2) bool {
+		if v.X() <= a.X()+ε || v.X() >= b.X()-ε {
+			return false
+		}
+		y := a.Y() + (b.Y()-a.Y())*(v.X()-a.X())/(b.X()-a.X())
+		return math.Abs(y-v.Y()) <= ε
+	}
+	onWall := func(v *dcel.Vertex, x, y1, y2 float64) bool {
+		return math.Abs(v.X()-x) <= ε && v.Y() > y1+ε && v.Y() < y2-ε
+	}
+	span := vs.within(tr.left, tr.right)
+
+	add(vs.find(pts[0]))
+	for _, v := range span {
+		if onLine(v, pts[0], pts[1]) {
+			add(v)
+		}
+	}
+	add(vs.find(pts[1]))
+	wall := vs.within(tr.right, tr.right)
+	for i := len(wall) - 1; i >= 0; i-- {
+		if onWall(wall[i], tr.right, tr.bot[right], tr.top[right]) {
+			add(wall[i])
+		}
+	}
+	add(vs.find(pts[2]))
+	for i := len(span) - 1; i >= 0; i-- {
+		if onLine(span[i], pts[3], pts[2]) {
+			add(span[i])
+		}
+	}
+	add(vs.find(pts[3]))
+	for _, v := range vs.within(tr.left, tr.left) {
+		if onWall(v, tr.left, tr.bot[left], tr.top[left]) {
+			add(v)
+		}
+	}
+	return poly
+}
+
 func (tn *Node) inOrder() []*Trapezoid {
+	seen := make(map[*Trapezoid]bool)
+	return tn.inOrderUnseen(seen)
+}
+
+// inOrderUnseen returns the trapezoids below tn which are
+// not in seen, as trapezoids can have more than one parent.
+func (tn *Node) inOrderUnseen(seen map[*Trapezoid]bool) []*Trapezoid {
 	if tn == nil {
 		// error, unless this is root,
 		// I think
@@ -77,46 +258,134 @@ func (tn *Node) inOrder() []*Trapezoid {
 	}
 	if tn.left == nil && tn.right == nil {
 		// This is a trapezoid (or should be)
-		return []*Trapezoid{tn.payload.(*Trapezoid)}
+		tr, ok := tn.payload.(*Trapezoid)
+		if !ok || seen[tr] {
+			return []*Trapezoid{}
+		}
+		seen[tr] = true
+		return []*Trapezoid{tr}
 	}
-	trs := tn.left.inOrder()
-	return append(trs, tn.right.inOrder()...)
+	trs := tn.left.inOrderUnseen(seen)
+	return append(trs, tn.right.inOrderUnseen(seen)...)
 }
 
 // PointLocate returns, from a given complex structure,
 // which substructure that point falls into, if any.
 // In the trapezoidal map, the query structure can
-// be point-located on.
+// be point-located on. Points on the boundaries of
+// faces are placed in a face touching them.
 func (tn *Node) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
 	}
+	pt := geom.Point{vs[0], vs[1], 0}
+	f := tn.face(pt)
+	// Plumb line may count points on the boundaries
+	// of faces outside all of them.
+	if outerFace := tn.payload.(*dcel.Face); f == outerFace {
+		return tn.locate(pt, f).Touching(outerFace), nil
+	}
+	return f, nil
+}
+
+// face returns the face whose interior pt is in,
+// or the outer face if pt is in no face's interior.
+func (tn *Node) face(pt geom.Point) *dcel.Face {
 	// A point query on the structure is equivalent to an
 	// edge query where both edges are the same.
-	pt := geom.Point{vs[0], vs[1], 0}
+	outerFace := tn.payload.(*dcel.Face)
 	trs := tn.Query(geom.FullEdge{pt, pt})
 	if len(trs) == 0 {
-		fmt.Println("No trapezoids found")
-		return nil, nil
+		return outerFace
 	}
-	faces := trs[0].faces
-	outerFace := tn.payload.(*dcel.Face)
-	fmt.Println("Potential faces found", faces)
-	if faces[0] != outerFace && faces[0].Contains(pt) {
-		return faces[0], nil
+	for _, f := range trs[0].faces {
+		if f != nil && f != outerFace && f.Contains(pt) {
+			return f
+		}
+	}
+	return outerFace
+}
+
+// BatchLocate point locates each of pts concurrently,
+// returning the face containing each point in order.
+func (tn *Node) BatchLocate(pts []geom.D2) ([]*dcel.Face, error) {
+	return pointLoc.BatchLocate(tn, pts)
+}
+
+// Locate returns where (x, y) lies, telling points on
+// vertices and edges apart from points inside faces.
+func (tn *Node) Locate(x, y float64) (pointLoc.Location, error) {
+	pt := geom.Point{x, y, 0}
+	return tn.locate(pt, tn.face(pt)), nil
+}
+
+// locate returns where pt lies, or pt inside f
+// if pt is on no vertex or edge.
+func (tn *Node) locate(pt geom.Point, f *dcel.Face) pointLoc.Location {
+	x, y := pt.X(), pt.Y()
+	edges := []*dcel.Edge{}
+	for _, tr := range tn.Query(geom.FullEdge{pt, pt}) {
+		edges = append(edges, tr.topEdge, tr.botEdge)
+		// Queries at a vertex go right, into a trapezoid whose left
+		// wall is at the vertex, which edges ending at the vertex
+		// only bound from its left neighbors.
+		if tr.leftPt.X() == x && tr.leftPt.Y() == y {
+			for _, n := range []*Trapezoid{tr.Neighbors[upleft], tr.Neighbors[botleft]} {
+				if n != nil {
+					edges = append(edges, n.topEdge, n.botEdge)
+				}
+			}
+		}
+	}
+	return pointLoc.Classify(pt, f, edges...)
+}
+
+// EdgeAbove returns the rightward half edge which forms
+// the top of the trapezoid containing (x, y), or nil if
+// that trapezoid is bounded above by the edge of the map.
+func (tn *Node) EdgeAbove(x, y float64) *dcel.Edge {
+	pt := geom.Point{x, y, 0}
+	trs := tn.Query(geom.FullEdge{pt, pt})
+	if len(trs) == 0 {
+		return nil
 	}
-	if faces[1] != outerFace && faces[1].Contains(pt) {
-		return faces[1], nil
+	return trs[0].topEdge
+}
+
+// EdgeBelow returns the leftward half edge which forms
+// the bottom of the trapezoid containing (x, y), or nil if
+// that trapezoid is bounded below by the edge of the map.
+func (tn *Node) EdgeBelow(x, y float64) *dcel.Edge {
+	pt := geom.Point{x, y, 0}
+	trs := tn.Query(geom.FullEdge{pt, pt})
+	if len(trs) == 0 {
+		return nil
 	}
-	return nil, nil
+	return trs[0].botEdge
 }
 
-// Query is shorthand for tn.query(fe, tn)
+// Query returns the trapezoids which fe passes through, from
+// left to right.
 func (tn *Node) Query(fe geom.FullEdge) []*Trapezoid {
+	return tn.search(ordered(fe), tn.trace())
+}
+
+// search returns the trapezoids which the ordered segment
+// fe passes through, tracing the nodes it visits to tracer.
+func (tn *Node) search(fe geom.FullEdge, tracer pointLoc.Tracer) []*Trapezoid {
 	if tn == nil {
 		return []*Trapezoid{}
 	}
-	return tn.query(fe, tn)
+	return tn.query(fe, tn, tracer)
+}
+
+// trace returns the Tracer held by tn, or a
+// NoTracer if tn does not hold one.
+func (tn *Node) trace() pointLoc.Tracer {
+	if tn == nil || tn.tracer == nil {
+		return pointLoc.NoTracer{}
+	}
+	return tn.tracer
 }
 
 func (tn *Node) discard(n *Node) {
@@ -127,7 +396,7 @@ func (tn *Node) discard(n *Node) {
 			p.right = n
 		}
 	}
-	n.parents = tn.parents
+	n.parents = append(n.parents, tn.parents...)
 }
 
 func (tn *Node) set(v int, n *Node) {
@@ -186,6 +455,6 @@ func NewRoot() *Node {
 	}
 }
 
-func rootQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
-	return n.left.Query(fe)
+func rootQuery(fe geom.FullEdge, n *Node, tracer pointLoc.Tracer) []*Trapezoid {
+	return n.left.search(fe, tracer)
 }
@@ -4,12 +4,7 @@
 package trapezoid
 
 import (
-	"fmt"
-	"image/color"
-
-	"github.com/oakmound/oak/physics"
 	"github.com/nylen/go-compgeo/dcel"
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
 	"github.com/nylen/go-compgeo/geom"
 	"github.com/nylen/go-compgeo/printutil"
 )
@@ -45,6 +40,14 @@ type Trapezoid struct {
 	Neighbors   [4]*Trapezoid
 	node        *Node
 	faces       [2]*dcel.Face
+	// The segments above and below the trapezoid,
+	// and the points which define its left and right walls.
+	// The values above are derived from these.
+	topSeg, botSeg  geom.FullEdge
+	leftPt, rightPt geom.Point
+	// The half edges of the input dcel along topSeg and botSeg
+	// whose faces are toward the trapezoid, if there are any.
+	topEdge, botEdge *dcel.Edge
 }
 
 func (tr *Trapezoid) GetNeighbors() (*Trapezoid, *Trapezoid, *Trapezoid, *Trapezoid) {
@@ -121,6 +124,12 @@ func (tr *Trapezoid) Copy() *Trapezoid {
 	tr2.right = tr.right
 	tr2.Neighbors = tr.Neighbors
 	tr2.faces = tr.faces
+	tr2.topSeg = tr.topSeg
+	tr2.botSeg = tr.botSeg
+	tr2.leftPt = tr.leftPt
+	tr2.rightPt = tr.rightPt
+	tr2.topEdge = tr.topEdge
+	tr2.botEdge = tr.botEdge
 	return tr2
 }
 
@@ -182,386 +191,143 @@ func newTrapezoid(sp geom.Span) *Trapezoid {
 	t := new(Trapezoid)
 	min := sp.At(geom.SPAN_MIN).(geom.Point)
 	max := sp.At(geom.SPAN_MAX).(geom.Point)
-	t.top[left] = max.Y()
-	t.top[right] = max.Y()
-	t.bot[left] = min.Y()
-	t.bot[right] = min.Y()
-	t.left = min.X()
-	t.right = max.X()
-	t.Neighbors = [4]*Trapezoid{nil, nil, nil, nil}
-	return t
-}
-
-func (tr *Trapezoid) toPhysics() []physics.Vector {
-	vs := make([]physics.Vector, 4)
-	for i, p := range tr.AsPoints() {
-		vs[i] = physics.NewVector(p.X(), p.Y())
+	t.topSeg = geom.FullEdge{
+		geom.NewPoint(min.X(), max.Y(), 0),
+		geom.NewPoint(max.X(), max.Y(), 0),
 	}
-	return vs
-}
-
-func (tr *Trapezoid) visualize() {
-	if tr == nil {
-		fmt.Println("Nothing to visualize")
-		return
+	t.botSeg = geom.FullEdge{
+		geom.NewPoint(min.X(), min.Y(), 0),
+		geom.NewPoint(max.X(), min.Y(), 0),
 	}
-	visualize.HighlightColor = visualize.AddFaceColor
-	visualize.DrawPoly(tr.toPhysics())
+	t.leftPt = geom.NewPoint(min.X(), min.Y(), 0)
+	t.rightPt = geom.NewPoint(max.X(), max.Y(), 0)
+	t.setBounds()
+	return t
 }
 
-func (tr *Trapezoid) visualizeNeighbors() {
-	if tr == nil {
-		return
-	}
-	visualize.HighlightColor = color.RGBA{128, 0, 128, 128}
-	for _, n := range tr.Neighbors {
-		if n == nil {
-			continue
-		}
-		visualize.DrawPoly(n.toPhysics())
-	}
+// piece returns the part of tr between the walls
+// through l and r.
+func (tr *Trapezoid) piece(l, r geom.Point) *Trapezoid {
+	t := new(Trapezoid)
+	t.topSeg = tr.topSeg
+	t.botSeg = tr.botSeg
+	t.leftPt = l
+	t.rightPt = r
+	t.faces = tr.faces
+	t.topEdge = tr.topEdge
+	t.botEdge = tr.botEdge
+	t.setBounds()
+	return t
 }
 
-// Replace neighbors runs replace neighbor for all directions
-// off of a trapezoid
-func (tr *Trapezoid) replaceNeighbors(rep, new *Trapezoid) {
-	if tr == nil {
-		return
-	}
-	for i := range tr.Neighbors {
-		tr.replaceNeighbor(i, rep, new)
-	}
+// setBounds sets tr's corner values from its
+// segments and wall points.
+func (tr *Trapezoid) setBounds() {
+	tr.left = tr.leftPt.X()
+	tr.right = tr.rightPt.X()
+	tr.top[left] = yAt(tr.topSeg, tr.left)
+	tr.top[right] = yAt(tr.topSeg, tr.right)
+	tr.bot[left] = yAt(tr.botSeg, tr.left)
+	tr.bot[right] = yAt(tr.botSeg, tr.right)
 }
 
-// Replace neighbor checks that the input is not nil,
-// and if it is not, if it's neighbor in the given direction is the
-// expected trapezoid to replace, replaces it with the given new trapezoid.
-func (tr *Trapezoid) replaceNeighbor(dir int, rep, new *Trapezoid) {
-	if tr == nil {
-		return
-	}
-	if tr.Neighbors[dir] == rep {
-		tr.Neighbors[dir] = new
+// yAt returns the y value of fe at x. Vertical
+// segments only bound trapezoids with no width, so
+// for those any y value on the segment will do.
+func yAt(fe geom.FullEdge, x float64) float64 {
+	a, b := fe[0], fe[1]
+	if x == a.X() || a.X() == b.X() {
+		return a.Y()
 	}
-}
-
-// Assign the neighbors of the trapezoid tr's upleft and upright
-// neighbors (if they exist) dependant on tr being replaced by
-// the two trapezoids u and b split at y value lpy.
-//
-//  ~ ~ ~ ~ ~ ~
-//    ul |  u
-//  ~ ~ ~ -lpy-----
-//    bl |  b
-//  ~ ~ ~ ~ ~ ~
-func (tr *Trapezoid) replaceLeftPointers(u, b *Trapezoid, lpy float64) {
-	replaceLeftPointers(tr, tr.Neighbors[upleft], tr.Neighbors[botleft], u, b, lpy)
-}
-
-// Given the trapezoid tr, being replaced by u and b where
-// u is above b and lpy is the point at which u and be connect
-// on tr's left edge, assign all pointers from ul and bl where ul
-// is above bl that previously pointed to tr to the appropriate
-// trapezoid of u and b.
-func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
-	if ul != nil && geom.F64eq(ul.bot[right], lpy) {
-		fmt.Println("Case 0")
-		// U matches exactly to ul,
-		// B matches exactly to bl.
-		//
-		//  ~ ~ ~ ~ ~ ~
-		//    ul |  u
-		// -----lpy-----
-		//    bl |  b
-		//  ~ ~ ~ ~ ~ ~
-		//
-		ul.replaceNeighbors(tr, u)
-		bl.replaceNeighbors(tr, b)
-	} else if (ul != nil && geom.F64eq(ul.top[right], lpy)) ||
-		(ul == nil && bl != nil && geom.F64eq(bl.top[right], lpy)) {
-		fmt.Println("Case 1")
-		// U does not border the left edge
-		//
-		// ~ ~ ~ lpy \
-		//  (ul)  \   \ u
-		// ~ ~ ~ ~ \ b \
-		//  (bl)    \   \
-		// ~ ~ ~ ~ ~ ~ ~
-		ul.replaceNeighbors(tr, b)
-		bl.replaceNeighbors(tr, b)
-		if ul != nil {
-			b.Neighbors[upleft] = ul
-		} else {
-			b.Neighbors[upleft] = bl
-		}
-		if bl != nil {
-			b.Neighbors[botleft] = bl
-		} else {
-			b.Neighbors[botleft] = ul
-		}
-		u.Lefts(b)
-	} else if (bl != nil && geom.F64eq(bl.bot[right], lpy)) ||
-		(bl == nil && ul != nil && geom.F64eq(ul.bot[right], lpy)) {
-		fmt.Println("Case 2")
-		// D does not border the left edge
-		//
-		// ~ ~ ~ ~ ~ ~ ~ ~
-		//  (ul)    /   /
-		// ~ ~ ~ ~ / u /
-		//  (bl)  /   / b
-		// ~ ~ ~ lpy / ~ ~
-		//
-		ul.replaceNeighbors(tr, u)
-		bl.replaceNeighbors(tr, u)
-		if bl != nil {
-			u.Neighbors[botleft] = bl
-		} else {
-			u.Neighbors[botleft] = ul
-		}
-		if ul != nil {
-			u.Neighbors[upleft] = ul
-		} else {
-			u.Neighbors[upleft] = bl
-		}
-		b.Lefts(u)
-	} else if ul != nil && ul.bot[right] < lpy {
-		fmt.Println("Case 3")
-		// UL expands past FE
-		//
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//   ul    |   u
-		//        lpy ~ ~ ~
-		// ~ ~ ~ ~ |   b
-		//   bl    |
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//
-		if ul != bl {
-			bl.replaceNeighbors(tr, b)
-		}
-		ul.replaceNeighbor(upright, tr, u)
-		ul.replaceNeighbor(botright, tr, b)
-		u.Lefts(ul)
-		b.Neighbors[upleft] = ul
-		b.Neighbors[botleft] = bl
-	} else if bl != nil && bl.top[right] > lpy {
-		fmt.Println("Case 4")
-		// BL expands past FE
-		//
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//   ul    |   u
-		// ~ ~ ~ ~ |
-		//         lpy ~ ~ ~
-		//   bl    |   b
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//
-		if ul != bl {
-			ul.replaceNeighbors(tr, u)
-		}
-		bl.replaceNeighbor(upright, tr, u)
-		bl.replaceNeighbor(botright, tr, b)
-		b.Lefts(bl)
-		u.Neighbors[upleft] = ul
-		u.Neighbors[botleft] = bl
-	} else {
-		fmt.Println("No left pointer case satisfied.")
-		fmt.Println("Both neighbors nil:", ul == nil && bl == nil)
-		fmt.Println(ul, bl)
-		fmt.Println(u, b)
-		fmt.Println(lpy)
+	if x == b.X() {
+		return b.Y()
 	}
+	return a.Y() + (b.Y()-a.Y())*(x-a.X())/(b.X()-a.X())
 }
 
-//  ~ ~ ~ ~ ~ ~
-//    u |  ur
-//  --rpy ~ ~ ~
-//    b |  br
-//  ~ ~ ~ ~ ~ ~
-func (tr *Trapezoid) replaceRightPointers(u, b *Trapezoid, rpy float64) {
-	replaceRightPointers(tr, tr.Neighbors[upright], tr.Neighbors[botright], u, b, rpy)
-}
-
-func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
-	if ur != nil && geom.F64eq(ur.bot[left], rpy) {
-		fmt.Println("Case 0")
-		// U matches exactly to ur,
-		// B matches exactly to br.
-		//
-		//  ~ ~ ~ ~ ~ ~
-		//    u  |  ur
-		// -----rpy-----
-		//    b  |  br
-		//  ~ ~ ~ ~ ~ ~
-		//
-		ur.replaceNeighbors(tr, u)
-		br.replaceNeighbors(tr, b)
-	} else if (ur != nil && geom.F64eq(ur.top[left], rpy)) ||
-		(ur == nil && br != nil && geom.F64eq(br.top[left], rpy)) {
-		fmt.Println("Right case 1")
-		// U does not border the right edge
-		//
-		//  ~ ~ rpy ~ ~ ~
-		//   u /   / (ur)
-		//    / b / ~ ~ ~
-		//   /   /  (br)
-		//  ~ ~ ~ ~ ~ ~
-		//
-		ur.replaceNeighbors(tr, b)
-		br.replaceNeighbors(tr, b)
-		u.Rights(b)
-		if ur != nil {
-			b.Neighbors[upright] = ur
-		} else {
-			b.Neighbors[upright] = br
-		}
-		if br != nil {
-			b.Neighbors[botright] = br
-		} else {
-			b.Neighbors[botright] = ur
-		}
-	} else if (br != nil && geom.F64eq(br.bot[left], rpy)) ||
-		(br == nil && ur != nil && geom.F64eq(ur.bot[left], rpy)) {
-		fmt.Println("Right case 2")
-		//
-		//  ~ ~ rpy ~ ~ ~
-		//  \   \    ur
-		//   \ u \ ~ ~ ~
-		//  b \   \ br
-		//  ~ ~ rpy ~ ~ ~
-		//
-		ur.replaceNeighbors(tr, u)
-		br.replaceNeighbors(tr, u)
-		b.Rights(u)
-		if ur != nil {
-			u.Neighbors[upright] = ur
-		} else {
-			u.Neighbors[upright] = br
-		}
-		if br != nil {
-			u.Neighbors[botright] = br
-		} else {
-			u.Neighbors[botright] = ur
-		}
-	} else if ur != nil && ur.bot[left] < rpy {
-		fmt.Println("Right case 3")
-		// UR expands past FE
-		//
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//   u     |   ur
-		// ~ ~ ~ ~rpy
-		//         | ~ ~ ~ ~
-		//   b     |   br
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//
-		if ur != br {
-			br.replaceNeighbors(tr, b)
-		}
-		ur.replaceNeighbor(upleft, tr, u)
-		ur.replaceNeighbor(botleft, tr, b)
-		u.Rights(ur)
-		b.Neighbors[upright] = ur
-		b.Neighbors[botright] = br
-	} else if br != nil && br.top[left] > rpy {
-		fmt.Println("Right case 4")
-		// BR expands past FE
-		//
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//   u     |   ur
-		//         | ~ ~ ~ ~
-		// ~ ~ ~ ~rpy   br
-		//   b     |
-		// ~ ~ ~ ~ ~ ~ ~ ~ ~
-		//
-		if ur != br {
-			ur.replaceNeighbors(tr, u)
-		}
-		br.replaceNeighbor(upleft, tr, u)
-		br.replaceNeighbor(botleft, tr, b)
-		b.Rights(br)
-		u.Neighbors[upright] = ur
-		u.Neighbors[botright] = br
-	} else {
-		fmt.Println("No right pointer case satisfied")
-		fmt.Println("Both neighbors nil:", br == nil && ur == nil)
+// lexLess reports whether a is left of b. Points with
+// equal x values are ordered by y, as if the plane were
+// sheared very slightly, so no two distinct points ever
+// share a wall.
+func lexLess(a, b geom.D2) bool {
+	if a.X() != b.X() {
+		return a.X() < b.X()
 	}
+	return a.Y() < b.Y()
 }
 
-func (tr *Trapezoid) twoRights(u, b *Trapezoid, lpy float64) {
-	tr.Neighbors[upright] = u
-	tr.Neighbors[botright] = b
-	if geom.F64eq(tr.top[right], lpy) {
-		tr.Neighbors[upright] = b
-	} else if geom.F64eq(tr.bot[right], lpy) {
-		tr.Neighbors[botright] = u
+// ordered returns fe with its left point, by lexLess, first.
+func ordered(fe geom.FullEdge) geom.FullEdge {
+	if lexLess(fe[1], fe[0]) {
+		return geom.FullEdge{fe[1], fe[0]}
 	}
-	u.Lefts(tr)
-	b.Lefts(tr)
+	return fe
 }
 
-func (tr *Trapezoid) twoLefts(u, b *Trapezoid, rpy float64) {
-	tr.Neighbors[upleft] = u
-	tr.Neighbors[botleft] = b
-	if geom.F64eq(tr.top[left], rpy) {
-		tr.Neighbors[upleft] = b
-	} else if geom.F64eq(tr.bot[left], rpy) {
-		tr.Neighbors[botleft] = u
+// rightward returns whichever of e and its twin
+// points right, as ordered by lexLess.
+func rightward(e *dcel.Edge) *dcel.Edge {
+	if lexLess(e.Twin.Origin, e.Origin) {
+		return e.Twin
 	}
-	u.Rights(tr)
-	b.Rights(tr)
-}
-
-func splitExactly(u, d *Trapezoid, fe geom.FullEdge) {
-	u.exactly(top, fe)
-	d.exactly(bot, fe)
+	return e
 }
 
-func (tr *Trapezoid) exactly(d int, fe geom.FullEdge) {
-	lp := fe.Left()
-	rp := fe.Right()
-	tr.left = lp.X()
-	tr.right = rp.X()
-	if d == bot {
-		tr.top[left] = lp.Y()
-		tr.top[right] = rp.Y()
-	} else if d == top {
-		tr.bot[left] = lp.Y()
-		tr.bot[right] = rp.Y()
+// twin returns the twin of e, or nil if e is nil.
+func twin(e *dcel.Edge) *dcel.Edge {
+	if e == nil {
+		return nil
 	}
+	return e.Twin
 }
 
-func annotatedVisualize(strs []string, trs []*Trapezoid) {
-	for i, s := range strs {
-		fmt.Println("Visualizing " + s)
-		trs[i].visualize()
-		//trs[i].visualizeNeighbors()
-		fmt.Print(s + " visualized.")
-	}
-	fmt.Println("")
+// isAbove reports whether p lies above the ordered
+// segment fe, or for vertical segments, to its left.
+func isAbove(p geom.D2, fe geom.FullEdge) bool {
+	return geom.Orient2D(fe[0], fe[1], p) > 0
 }
 
-func (tr *Trapezoid) setBotleft(fe geom.FullEdge) {
-	r := tr.right
-	if r > fe.Right().X() {
-		r = fe.Right().X()
+// linkNeighbors sets the neighbors of the trapezoids in created,
+// and of the remaining neighbors of the trapezoids in removed.
+// In the sheared plane, a trapezoid b is a right neighbor of
+// a exactly when b's left wall is a's right wall, and they share
+// a top segment (its upper right neighbor) or a bottom segment
+// (its lower right neighbor).
+func linkNeighbors(removed, created []*Trapezoid) {
+	gone := make(map[*Trapezoid]bool)
+	for _, tr := range removed {
+		gone[tr] = true
 	}
-	l := tr.left
-	if l < fe.Left().X() {
-		l = fe.Left().X()
+	cands := append([]*Trapezoid{}, created...)
+	seen := make(map[*Trapezoid]bool)
+	for _, tr := range removed {
+		for _, n := range tr.Neighbors {
+			if n == nil || gone[n] || seen[n] {
+				continue
+			}
+			seen[n] = true
+			for i, n2 := range n.Neighbors {
+				if gone[n2] {
+					n.Neighbors[i] = nil
+				}
+			}
+			cands = append(cands, n)
+		}
 	}
-	edge, _ := fe.SubEdge(0, l, r)
-	tr.bot[left] = edge.Left().Y()
-	tr.bot[right] = edge.Right().Y()
-}
-
-func (tr *Trapezoid) setTopleft(fe geom.FullEdge) {
-	r := tr.right
-	if r > fe.Right().X() {
-		r = fe.Right().X()
+	byLeft := make(map[geom.Point][]*Trapezoid)
+	for _, tr := range cands {
+		byLeft[tr.leftPt] = append(byLeft[tr.leftPt], tr)
 	}
-	l := tr.left
-	if l < fe.Left().X() {
-		l = fe.Left().X()
+	for _, a := range cands {
+		for _, b := range byLeft[a.rightPt] {
+			if a.topSeg == b.topSeg {
+				a.Neighbors[upright] = b
+				b.Neighbors[upleft] = a
+			}
+			if a.botSeg == b.botSeg {
+				a.Neighbors[botright] = b
+				b.Neighbors[botleft] = a
+			}
+		}
 	}
-	edge, _ := fe.SubEdge(0, l, r)
-	tr.top[left] = edge.Left().Y()
-	tr.top[right] = edge.Right().Y()
 }
@@ -1,9 +1,7 @@
 package trapezoid
 
 import (
-	"image/color"
-
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 )
 
@@ -17,40 +15,25 @@ func NewTrapNode(tr *Trapezoid) *Node {
 	return node
 }
 
-func trapQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
+func trapQuery(fe geom.FullEdge, n *Node, tracer pointLoc.Tracer) []*Trapezoid {
 	tr := n.payload.(*Trapezoid)
 	traps := []*Trapezoid{tr}
-	if visualize.VisualCh != nil {
-		visualize.HighlightColor = color.RGBA{0, 0, 128, 128}
-		visualize.DrawPoly(tr.toPhysics())
-	}
-	r := fe.Right()
-	for tr != nil && r.X() > tr.right {
-		// We perform this check here is it is less expensive
-		// than the cross product in the latter case, even
-		// though the latter case would suffice to do this.
-		if tr.Neighbors[upright] == tr.Neighbors[botright] {
+	tracer.RegionChecked(tr.AsPoints())
+	// Follow fe to the right through the map. When fe
+	// leaves tr through its right wall, it enters the
+	// upper right neighbor of tr if tr's right point is
+	// below fe, and the lower right neighbor otherwise.
+	for lexLess(tr.rightPt, fe[1]) {
+		if isAbove(tr.rightPt, fe) {
 			tr = tr.Neighbors[botright]
 		} else {
-			// If the edge separating the two
-			// trapezoids to the right of tr from one another
-			// is a above the query segment, then we also intersect
-			// the bottom trapezoid.
-			// For this aboveness check we just use the left endpoint
-			// of the separating edge, as we know that is within fe's
-			// horizontal span.
-			if geom.IsAbove(
-				tr.Neighbors[upright].BotEdge().Left(), fe.Left(), fe.Right()) {
-				tr = tr.Neighbors[botright]
-			} else {
-				tr = tr.Neighbors[upright]
-			}
+			tr = tr.Neighbors[upright]
 		}
-		if tr != nil {
-			visualize.HighlightColor = visualize.CheckFaceColor
-			visualize.DrawPoly(tr.toPhysics())
-			traps = append(traps, tr)
+		if tr == nil {
+			break
 		}
+		tracer.RegionChecked(tr.AsPoints())
+		traps = append(traps, tr)
 	}
 	return traps
 }
@@ -1,9 +1,7 @@
 package trapezoid
 
 import (
-	"image/color"
-
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 )
 
@@ -15,17 +13,12 @@ func NewX(p geom.D3) *Node {
 	}
 }
 
-func xQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
+func xQuery(fe geom.FullEdge, n *Node, tracer pointLoc.Tracer) []*Trapezoid {
 	p := n.payload.(geom.Point)
-	if visualize.VisualCh != nil {
-		visualize.HighlightColor = color.RGBA{128, 128, 128, 128}
-		visualize.DrawVerticalLine(p)
-	}
-	if geom.F64eq(fe.Left().X(), p.X()) {
-		// If equal, go right.
-		return n.right.Query(fe)
-	} else if fe.Left().X() < p.X() {
-		return n.left.Query(fe)
+	tracer.SweepLine(p)
+	// If equal, go right.
+	if lexLess(fe[0], p) {
+		return n.left.search(fe, tracer)
 	}
-	return n.right.Query(fe)
+	return n.right.search(fe, tracer)
 }
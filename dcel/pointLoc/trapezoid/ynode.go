@@ -1,13 +1,12 @@
 package trapezoid
 
 import (
-	"image/color"
-
-	"github.com/nylen/go-compgeo/dcel/pointLoc/visualize"
+	"github.com/nylen/go-compgeo/dcel/pointLoc"
 	"github.com/nylen/go-compgeo/geom"
 )
 
-// NewY returns a Y-Node at edge e
+// NewY returns a Y-Node at edge e, which should
+// be ordered.
 func NewY(e geom.FullEdge) *Node {
 	return &Node{
 		query:   yQuery,
@@ -15,28 +14,19 @@ func NewY(e geom.FullEdge) *Node {
 	}
 }
 
-func yQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
-	// This query asks if fe.Left() is above or below
-	// yn.FullEdge.
-	// If they are colinear, however, we need to check
-	// which slope is larger. If fe is larger, we go above,
-	// else we go below.
+func yQuery(fe geom.FullEdge, n *Node, tracer pointLoc.Tracer) []*Trapezoid {
+	// This query asks if fe's left point is above or below
+	// yn.
+	// If fe starts on yn, as edges which share an endpoint do,
+	// we instead check whether fe's right point is above yn.
 	yn := n.payload.(geom.FullEdge)
-	if visualize.VisualCh != nil {
-		visualize.HighlightColor = color.RGBA{128, 128, 128, 128}
-		visualize.DrawLine(yn.Left(), yn.Right())
-	}
-	cp := geom.HzCross2D(fe.Left(), yn.Left(), yn.Right())
-	if cp > 0 {
-		return n.left.Query(fe)
-	} else if cp < 0 {
-		return n.right.Query(fe)
+	tracer.EdgeChecked(yn.Left(), yn.Right())
+	cp := geom.Orient2D(yn[0], yn[1], fe[0])
+	if cp == 0 {
+		cp = geom.Orient2D(yn[0], yn[1], fe[1])
 	}
-	// The colinear case
-	s1 := fe.Slope()
-	s2 := yn.Slope()
-	if s1 > s2 {
-		return n.left.Query(fe)
+	if cp >= 0 {
+		return n.left.search(fe, tracer)
 	}
-	return n.right.Query(fe)
+	return n.right.search(fe, tracer)
 }
@@ -14,17 +14,6 @@ import (
 )
 
 var (
-	// VisualCh is used to determine if
-	// this process is currently visualizing anything.
-	// if this is nil, the program will not attempt to
-	// send visuals.
-	VisualCh chan *Visual
-	// HighlightColor is the color that (right now)
-	// will be assigned to every Visual generated.
-	HighlightColor = color.RGBA{255, 255, 255, 255}
-	// HighlightLayer is the layer that (right now)
-	// will be assigned to every Visual generated.
-	HighlightLayer = 10
 	// Default color sets
 	AddColor       = color.RGBA{0, 255, 0, 255}
 	RemoveColor    = color.RGBA{255, 0, 0, 255}
@@ -41,63 +30,129 @@ type Visual struct {
 	Layer int
 }
 
-// DrawLine sends a line instruction to the Visual Channel
-func DrawLine(p1, p2 geom.D2) {
-	if VisualCh == nil {
+// A Visualizer is a pointLoc.Tracer which sends a Visual
+// for each step it is told of over its channel.
+type Visualizer struct {
+	// Ch is used to determine if this visualizer is
+	// currently visualizing anything. If this is nil,
+	// the visualizer will not attempt to send visuals.
+	Ch chan *Visual
+	// Layer is the layer that (right now) will be
+	// assigned to every Visual generated.
+	Layer int
+}
+
+// NewVisualizer returns a Visualizer drawing on layer 10
+// which has not yet been given a channel to send over.
+func NewVisualizer() *Visualizer {
+	return &Visualizer{Layer: 10}
+}
+
+func (vz *Visualizer) active() bool {
+	return vz != nil && vz.Ch != nil
+}
+
+func (vz *Visualizer) send(r render.Renderable) {
+	v := new(Visual)
+	v.Renderable = r
+	v.Layer = vz.Layer
+	vz.Ch <- v
+}
+
+// DrawLine sends a line instruction of color c to the Visual Channel
+func (vz *Visualizer) DrawLine(p1, p2 geom.D2, c color.Color) {
+	if !vz.active() {
 		return
 	}
-	v := new(Visual)
-	v.Renderable = render.NewThickLine(p1.X(), p1.Y(), p2.X(), p2.Y(), HighlightColor, 2)
-	v.Layer = HighlightLayer
-	VisualCh <- v
+	vz.send(render.NewThickLine(p1.X(), p1.Y(), p2.X(), p2.Y(), c, 2))
 }
 
 // DrawVerticalLine sends a line extending through the screen
-// vertically to the visual channel at a given point
-func DrawVerticalLine(p geom.D2) {
-	if VisualCh == nil {
+// vertically of color c to the visual channel at a given point
+func (vz *Visualizer) DrawVerticalLine(p geom.D2, c color.Color) {
+	if !vz.active() {
 		return
 	}
-	v := new(Visual)
 	y1 := p.Y() - 480
 	y2 := p.Y() + 480
-	v.Renderable = render.NewThickLine(p.X(), y1, p.X(), y2, HighlightColor, 1)
-	v.Layer = HighlightLayer
-	VisualCh <- v
+	vz.send(render.NewThickLine(p.X(), y1, p.X(), y2, c, 1))
 }
 
 // DrawPoly sends a polygon made up of ps (assumed convex)
-// to the visual channel
-func DrawPoly(ps []physics.Vector) {
-	if VisualCh == nil {
+// filled with c to the visual channel
+func (vz *Visualizer) DrawPoly(ps []physics.Vector, c color.Color) {
+	if !vz.active() {
 		return
 	}
-	v := new(Visual)
-	var err error
-	v.Renderable, err = render.NewPolygon(ps)
+	poly, err := render.NewPolygon(ps)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	v.Renderable.(*render.Polygon).Fill(HighlightColor)
+	poly.Fill(c)
+	vz.send(poly)
+}
 
-	v.Layer = HighlightLayer
-	VisualCh <- v
+// DrawPoints draws the polygon with corners ps,
+// as DrawPoly does.
+func (vz *Visualizer) DrawPoints(ps []geom.D2, c color.Color) {
+	if !vz.active() || len(ps) < 3 {
+		return
+	}
+	physVerts := make([]physics.Vector, len(ps))
+	for i, p := range ps {
+		physVerts[i] = physics.NewVector(p.X(), p.Y())
+	}
+	vz.DrawPoly(physVerts, c)
 }
 
 // DrawFace converts a face into a polygon, then
-// draws it as a polygon.
-func DrawFace(f *dcel.Face) {
-	if VisualCh == nil || f == nil {
+// draws it as a polygon filled with c.
+func (vz *Visualizer) DrawFace(f *dcel.Face, c color.Color) {
+	if !vz.active() || f == nil {
 		return
 	}
 	ps := f.Vertices()
-	physVerts := make([]physics.Vector, len(ps))
+	ds := make([]geom.D2, len(ps))
 	for i, v := range ps {
-		physVerts[i] = physics.NewVector(v.X(), v.Y())
-	}
-	if len(physVerts) < 3 {
-		return
+		ds[i] = v
 	}
-	DrawPoly(physVerts)
+	vz.DrawPoints(ds, c)
+}
+
+// SweepLine draws a vertical line through p.
+func (vz *Visualizer) SweepLine(p geom.D2) {
+	vz.DrawVerticalLine(p, CheckLineColor)
+}
+
+// EdgeAdded draws the edge from a to b in AddColor.
+func (vz *Visualizer) EdgeAdded(a, b geom.D2) {
+	vz.DrawLine(a, b, AddColor)
+}
+
+// EdgeRemoved draws the edge from a to b in RemoveColor.
+func (vz *Visualizer) EdgeRemoved(a, b geom.D2) {
+	vz.DrawLine(a, b, RemoveColor)
+}
+
+// EdgeChecked draws the edge from a to b in CheckLineColor.
+func (vz *Visualizer) EdgeChecked(a, b geom.D2) {
+	vz.DrawLine(a, b, CheckLineColor)
+}
+
+// FaceChecked fills f with CheckFaceColor.
+func (vz *Visualizer) FaceChecked(f *dcel.Face) {
+	vz.DrawFace(f, CheckFaceColor)
+}
+
+// TrapezoidCreated fills the trapezoid with
+// the given corners with AddFaceColor.
+func (vz *Visualizer) TrapezoidCreated(corners []geom.D2) {
+	vz.DrawPoints(corners, AddFaceColor)
+}
+
+// RegionChecked fills the region with the
+// given corners with CheckFaceColor.
+func (vz *Visualizer) RegionChecked(corners []geom.D2) {
+	vz.DrawPoints(corners, CheckFaceColor)
 }
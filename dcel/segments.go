@@ -0,0 +1,27 @@
+package dcel
+
+import (
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+)
+
+// FromSegments returns the subdivision formed by segs. Segments
+// are split wherever they cross or touch, and pieces of segments
+// which overlap become one edge. Points within ε of each other
+// become one vertex. The outer face is at OUTER_FACE, and
+// components of segs which lie inside a bounded face are holes in
+// that face. Only the x and y values of segs are considered, and
+// the vertices of the dcel lie at z = 0. If segs has no segments
+// of non-zero length, FromSegments returns an EmptyError.
+func FromSegments(segs []geom.FullEdge) (*DCEL, error) {
+	pts, err := intersect.Sweep(segs)
+	if err != nil {
+		return nil, err
+	}
+	if len(pts) == 0 {
+		return nil, compgeo.EmptyError{}
+	}
+	dc, _ := arrange(segs, pts)
+	return dc, nil
+}
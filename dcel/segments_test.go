@@ -0,0 +1,86 @@
+package dcel_test
+
+import (
+	"testing"
+
+	compgeo "github.com/nylen/go-compgeo"
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func segment(x1, y1, x2, y2 float64) geom.FullEdge {
+	return geom.FullEdge{geom.NewPoint(x1, y1, 0), geom.NewPoint(x2, y2, 0)}
+}
+
+func TestFromSegmentsGrid(t *testing.T) {
+	// A square cut into nine by two lines each way
+	dc, err := dcel.FromSegments([]geom.FullEdge{
+		segment(0, 0, 3, 0),
+		segment(3, 0, 3, 3),
+		segment(3, 3, 0, 3),
+		segment(0, 3, 0, 0),
+		segment(1, 0, 1, 3),
+		segment(2, 3, 2, 0),
+		segment(0, 1, 3, 1),
+		segment(0, 2, 3, 2),
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Empty(t, dc.Validate())
+	assert.Equal(t, 16, len(dc.Vertices))
+	assert.Equal(t, 48, len(dc.HalfEdges))
+	assert.Equal(t, 10, len(dc.Faces))
+	for _, f := range dc.Faces[dcel.OUTER_FACE+1:] {
+		assert.Equal(t, 4, len(f.Vertices()))
+	}
+	found, err := dc.SelfIntersections()
+	assert.Nil(t, err)
+	assert.Empty(t, found)
+	assert.Equal(t, dc.Faces[dcel.OUTER_FACE], locate(dc, geom.NewPoint(4, 1, 0)))
+}
+
+func TestFromSegmentsMerge(t *testing.T) {
+	// A triangle whose corners are not quite closed, with
+	// an overlapping edge and a dangling segment.
+	dc, err := dcel.FromSegments([]geom.FullEdge{
+		segment(0, 0, 10, 0),
+		segment(10, 1e-9, 5, 10),
+		segment(5, 10, 0, 1e-9),
+		segment(2, 0, 8, 0),
+		segment(5, 10, 5, 20),
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Empty(t, dc.Validate())
+	assert.Equal(t, 2, len(dc.Faces))
+	assert.Equal(t, 6, len(dc.Vertices))
+	assert.Equal(t, dc.Faces[1], locate(dc, geom.NewPoint(5, 5, 0)))
+}
+
+func TestFromSegmentsNested(t *testing.T) {
+	dc, err := dcel.FromSegments([]geom.FullEdge{
+		segment(0, 0, 10, 0),
+		segment(10, 0, 10, 10),
+		segment(10, 10, 0, 10),
+		segment(0, 10, 0, 0),
+		segment(4, 4, 6, 4),
+		segment(6, 4, 5, 6),
+		segment(5, 6, 4, 4),
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Empty(t, dc.Validate())
+	assert.Equal(t, 3, len(dc.Faces))
+	assert.Equal(t, 1, len(locate(dc, geom.NewPoint(1, 1, 0)).Inner))
+}
+
+func TestFromSegmentsEmpty(t *testing.T) {
+	_, err := dcel.FromSegments(nil)
+	assert.Equal(t, compgeo.EmptyError{}, err)
+	_, err = dcel.FromSegments([]geom.FullEdge{segment(1, 1, 1, 1)})
+	assert.Equal(t, compgeo.EmptyError{}, err)
+}
@@ -1,6 +1,7 @@
 package dcel
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/nylen/go-compgeo/geom"
@@ -115,7 +116,7 @@ func FourPoint(p1, p2, p3, p4 geom.D3) *DCEL {
 	dc.HalfEdges[3].Prev = dc.HalfEdges[1]
 
 	dc.Faces[0].Outer = dc.HalfEdges[0]
-	dc.Faces[1].Inner = dc.HalfEdges[1]
+	dc.Faces[1].Inner = []*Edge{dc.HalfEdges[1]}
 
 	// Correcting for faces[0] = the infinite exterior
 	dc.Faces[0], dc.Faces[1] = dc.Faces[1], dc.Faces[0]
@@ -125,11 +126,34 @@ func FourPoint(p1, p2, p3, p4 geom.D3) *DCEL {
 	return dc
 }
 
-func goodrandf64() float64 {
-	return (rand.Float64() * (8.0 / 10.0)) + .1
+func goodrandf64(rnd *rand.Rand) float64 {
+	f := rand.Float64
+	if rnd != nil {
+		f = rnd.Float64
+	}
+	return (f() * (8.0 / 10.0)) + .1
 }
 
+// Random2DDCEL returns a size by size square DCEL, split
+// by splits random edges, using the global source of math/rand.
 func Random2DDCEL(size float64, splits int) *DCEL {
+	return Random2DDCELWithRand(size, splits, nil)
+}
+
+// Random2DDCELWithSeed acts as Random2DDCEL, drawing from
+// a new source seeded with seed.
+func Random2DDCELWithSeed(size float64, splits int, seed int64) *DCEL {
+	return Random2DDCELWithRand(size, splits, rand.New(rand.NewSource(seed)))
+}
+
+// Random2DDCELWithRand acts as Random2DDCEL, drawing from rnd,
+// so that equally seeded rnds produce identical DCELs. If rnd
+// is nil, the global source of math/rand is used.
+func Random2DDCELWithRand(size float64, splits int, rnd *rand.Rand) *DCEL {
+	intn := rand.Intn
+	if rnd != nil {
+		intn = rnd.Intn
+	}
 	// Generate a bounding box as a DCEL with one face
 	dc := FourPoint(
 		geom.NewPoint(0, 0, 0),
@@ -141,7 +165,7 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 
 	for i := 0; i < splits; i++ {
 		// choose a random face of the dcel
-		fi := rand.Intn(len(dc.Faces)-1) + 1
+		fi := intn(len(dc.Faces)-1) + 1
 		f := dc.Faces[fi]
 		// fmt.Println("Face", fi, f)
 		// choose two random edges of that face
@@ -155,20 +179,25 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 				break
 			}
 		}
-		r1 := rand.Intn(len(edges))
-		r2 := rand.Intn(len(edges))
-		if r2 == r1 {
-			r2 = (r2 + 1) % len(edges)
-		}
+		r1 := intn(len(edges))
 		e1 := edges[r1]
-		e2 := edges[r2]
+		// Splitting two edges along the same line would
+		// give us a face with no area, so e2 is chosen
+		// from the edges not colinear with e1.
+		others := []*Edge{}
+		for _, e := range edges {
+			if !colinear(e1, e) {
+				others = append(others, e)
+			}
+		}
+		e2 := others[intn(len(others))]
 		// fmt.Println("Edges chosen")
 		// fmt.Println("e1,e2", e1, e2)
 		// On each edge choose a random point
 		// We add some correction on this randomness
 		// so that we avoid having extremely small faces
-		v1 := PointToVertex(e1.PointAlong(0, goodrandf64()))
-		v2 := PointToVertex(e2.PointAlong(0, goodrandf64()))
+		v1 := PointToVertex(e1.PointAlong(0, goodrandf64(rnd)))
+		v2 := PointToVertex(e2.PointAlong(0, goodrandf64(rnd)))
 		// Add new vertices to dc at p1 and p2,
 		dc.Vertices = append(dc.Vertices, v1, v2)
 		// Split e1 and e2 and their twins at v1 and v2
@@ -270,3 +299,79 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 
 	return dc
 }
+
+// colinear reports whether e1 and e2 lie along the same line,
+// within the error introduced by splitting edges. We measure
+// against the line through the two endpoints farthest apart,
+// as the direction of a short edge can be far off from the
+// line it was split from.
+func colinear(e1, e2 *Edge) bool {
+	ps := []*Vertex{e1.Origin, e1.Twin.Origin, e2.Origin, e2.Twin.Origin}
+	var a, b *Vertex
+	l := -1.0
+	for i, p := range ps {
+		for _, q := range ps[i+1:] {
+			d := math.Hypot(p.X()-q.X(), p.Y()-q.Y())
+			if d > l {
+				a, b, l = p, q, d
+			}
+		}
+	}
+	for _, p := range ps {
+		if math.Abs(geom.Cross2D(a, b, p)) > 1e-9*l*l {
+			return false
+		}
+	}
+	return true
+}
+
+// Polygon creates a dcel from pts, connected in order around
+// one face, as FourPoint does for four points. pts should be
+// the corners of a simple polygon, in either direction. If
+// the points in pts have z values, their vertices keep them.
+func Polygon(pts []geom.D2) *DCEL {
+	n := len(pts)
+	dc := New()
+	dc.Vertices = make([]*Vertex, n)
+	for i, p := range pts {
+		z := 0.0
+		if p3, ok := p.(geom.D3); ok {
+			z = p3.Z()
+		}
+		dc.Vertices[i] = NewVertex(p.X(), p.Y(), z)
+	}
+	// The bounded face is to the right of its edges, so
+	// if pts run counter-clockwise, it is on the twins.
+	area := 0.0
+	for i, p := range pts {
+		q := pts[(i+1)%n]
+		area += p.X()*q.Y() - q.X()*p.Y()
+	}
+	f := NewFace()
+	outer := dc.Faces[OUTER_FACE]
+	dc.Faces = append(dc.Faces, f)
+	dc.HalfEdges = make([]*Edge, 2*n)
+	for i, v := range dc.Vertices {
+		e := NewEdge()
+		e.Origin = v
+		t := NewEdge()
+		t.Origin = dc.Vertices[(i+1)%n]
+		e.SetTwin(t)
+		e.Face, t.Face = f, outer
+		if area > 0 {
+			e.Face, t.Face = outer, f
+		}
+		v.OutEdge = e
+		dc.HalfEdges[2*i], dc.HalfEdges[2*i+1] = e, t
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		dc.HalfEdges[2*i].SetNext(dc.HalfEdges[2*j])
+		dc.HalfEdges[2*j+1].SetNext(dc.HalfEdges[2*i+1])
+	}
+	f.Outer, outer.Inner = dc.HalfEdges[0], []*Edge{dc.HalfEdges[1]}
+	if area > 0 {
+		f.Outer, outer.Inner = dc.HalfEdges[1], []*Edge{dc.HalfEdges[0]}
+	}
+	return dc
+}
@@ -12,20 +12,22 @@ func (dc *DCEL) Bounds() geom.Span {
 	return sp
 }
 
-// Bounds returns a Span calculated from
-// every point on the Inner of this face
-// because at time of writing we don't
-// populate Outer
+// Bounds returns a Span calculated from every point
+// on the boundaries of this face. As holes lie within
+// a face's outer boundary, for faces other than the
+// outer face this is the span of the outer boundary.
 func (f *Face) Bounds() geom.Span {
 	sp := geom.NewSpan()
 	if f == nil {
 		return sp
 	}
-	e := f.Outer
-	sp = sp.Expand(e.Origin)
-	for e.Next != f.Outer {
-		e = e.Next
+	for _, start := range f.Boundaries() {
+		e := start
 		sp = sp.Expand(e.Origin)
+		for e.Next != start {
+			e = e.Next
+			sp = sp.Expand(e.Origin)
+		}
 	}
 	return sp
 }
@@ -0,0 +1,321 @@
+package dcel
+
+import "strconv"
+
+// An IssueKind classifies a ValidationIssue. The kind determines
+// which of the DCEL's slices an issue's Index refers to.
+type IssueKind int
+
+const (
+	// MissingField is reported when an element lacks a reference
+	// it must have: an edge without an origin, twin, next, prev
+	// or face, a vertex without an out edge, or a face other than
+	// the outer face with neither an inner nor an outer edge. Index
+	// is into Vertices, HalfEdges or Faces, as named by Element.
+	MissingField IssueKind = iota
+	// UnknownElement is reported when an element refers to a vertex,
+	// edge or face which is not in the DCEL. Index and Element name
+	// the element holding the reference.
+	UnknownElement
+	// TwinMismatch is reported when HalfEdges[Index].Twin is the
+	// edge itself, or does not have the edge as its own twin.
+	TwinMismatch
+	// TwinPairing is reported when HalfEdges[Index] and
+	// HalfEdges[Index+1] are not twins, for even Index, or when
+	// there are an odd number of half edges.
+	TwinPairing
+	// NextPrevMismatch is reported when HalfEdges[Index].Next
+	// does not have the edge as its Prev, or its Prev does not
+	// have the edge as its Next.
+	NextPrevMismatch
+	// EndpointMismatch is reported when HalfEdges[Index].Next
+	// does not start where the edge's twin starts.
+	EndpointMismatch
+	// FaceMismatch is reported when HalfEdges[Index] is in the
+	// edge cycle of Faces[Related] but does not point to it.
+	FaceMismatch
+	// OpenCycle is reported when following Next from the edges
+	// of Faces[Index] does not lead back to where it started.
+	OpenCycle
+	// OutEdgeOrigin is reported when the OutEdge of
+	// Vertices[Index] does not have the vertex as its origin.
+	OutEdgeOrigin
+	// EulerMismatch is reported when the counts of vertices,
+	// edges, faces and connected components do not satisfy
+	// Euler's formula. For plane subdivisions, whose edges reach
+	// the outer face, this is V - E + F = 1 + C. For closed
+	// surfaces, where nothing is on the outer face and it is not
+	// counted, V - E + F = 2C - 2g for some genus g >= 0.
+	// Index is -1.
+	EulerMismatch
+	// SplitVertex is reported when walking around Vertices[Index],
+	// from its out edge to the next edge of each edge's twin, does
+	// not reach every edge starting at it, as when two faces
+	// meet at the vertex but their outer boundaries do not.
+	SplitVertex
+)
+
+var issueNames = []string{
+	"missing field",
+	"unknown element",
+	"twin mismatch",
+	"twin pairing",
+	"next/prev mismatch",
+	"endpoint mismatch",
+	"face mismatch",
+	"open cycle",
+	"out edge origin",
+	"euler mismatch",
+	"split vertex",
+}
+
+func (ik IssueKind) String() string {
+	if int(ik) < len(issueNames) {
+		return issueNames[ik]
+	}
+	return "unknown issue"
+}
+
+// An ElementType names which of a DCEL's slices
+// an index refers to.
+type ElementType int
+
+// Element types
+const (
+	VertexElement ElementType = iota
+	EdgeElement
+	FaceElement
+	NoElement
+)
+
+var elementNames = []string{"vertex", "edge", "face", "dcel"}
+
+func (et ElementType) String() string {
+	if int(et) < len(elementNames) {
+		return elementNames[et]
+	}
+	return "unknown element"
+}
+
+// A ValidationIssue describes one way in which a DCEL is
+// malformed, and which element it was found on.
+type ValidationIssue struct {
+	Kind    IssueKind
+	Element ElementType
+	// Index is the index of the offending element within
+	// the slice named by Element.
+	Index int
+	// Related is the index of a second element involved
+	// in the issue, or -1 if there is none.
+	Related int
+	// Detail describes the issue further, if needed.
+	Detail string
+}
+
+func (vi ValidationIssue) Error() string {
+	s := vi.Kind.String() + " at " + vi.Element.String()
+	if vi.Index >= 0 {
+		s += " " + strconv.Itoa(vi.Index)
+	}
+	if vi.Related >= 0 {
+		s += " (related " + strconv.Itoa(vi.Related) + ")"
+	}
+	if vi.Detail != "" {
+		s += ": " + vi.Detail
+	}
+	return s
+}
+
+// Validate checks the structure of dc, returning every issue
+// found, or nil if there are none. It checks that twins are
+// symmetric and paired in HalfEdges, that Next and Prev agree,
+// that every edge on a face's cycle points to that face, that
+// vertices' out edges start at them and lead around them to every
+// edge starting at them, and that dc satisfies
+// Euler's formula. Geometry, such as whether faces overlap,
+// is not checked.
+func (dc *DCEL) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	report := func(k IssueKind, et ElementType, i, related int, detail string) {
+		issues = append(issues, ValidationIssue{k, et, i, related, detail})
+	}
+	vIndex := make(map[*Vertex]int, len(dc.Vertices))
+	for i, v := range dc.Vertices {
+		vIndex[v] = i
+	}
+	eIndex := make(map[*Edge]int, len(dc.HalfEdges))
+	for i, e := range dc.HalfEdges {
+		eIndex[e] = i
+	}
+	fIndex := make(map[*Face]int, len(dc.Faces))
+	for i, f := range dc.Faces {
+		fIndex[f] = i
+	}
+	knownEdge := func(et ElementType, i int, e *Edge, field string) bool {
+		if e == nil {
+			report(MissingField, et, i, -1, field)
+			return false
+		}
+		if _, ok := eIndex[e]; !ok {
+			report(UnknownElement, et, i, -1, field)
+			return false
+		}
+		return true
+	}
+
+	for i, v := range dc.Vertices {
+		if knownEdge(VertexElement, i, v.OutEdge, "OutEdge") && v.OutEdge.Origin != v {
+			report(OutEdgeOrigin, VertexElement, i, eIndex[v.OutEdge], "")
+		}
+	}
+
+	if len(dc.HalfEdges)%2 != 0 {
+		report(TwinPairing, EdgeElement, len(dc.HalfEdges)-1, -1, "odd number of half edges")
+	}
+	for i, e := range dc.HalfEdges {
+		if e.Origin == nil {
+			report(MissingField, EdgeElement, i, -1, "Origin")
+		} else if _, ok := vIndex[e.Origin]; !ok {
+			report(UnknownElement, EdgeElement, i, -1, "Origin")
+		}
+		if e.Face == nil {
+			report(MissingField, EdgeElement, i, -1, "Face")
+		} else if _, ok := fIndex[e.Face]; !ok {
+			report(UnknownElement, EdgeElement, i, -1, "Face")
+		}
+		twinOk := knownEdge(EdgeElement, i, e.Twin, "Twin")
+		if twinOk && (e.Twin == e || e.Twin.Twin != e) {
+			report(TwinMismatch, EdgeElement, i, eIndex[e.Twin], "")
+			twinOk = false
+		}
+		if i%2 == 0 && i+1 < len(dc.HalfEdges) && e.Twin != dc.HalfEdges[i+1] {
+			report(TwinPairing, EdgeElement, i, i+1, "")
+		}
+		if knownEdge(EdgeElement, i, e.Next, "Next") {
+			if e.Next.Prev != e {
+				report(NextPrevMismatch, EdgeElement, i, eIndex[e.Next], "Next.Prev")
+			}
+			if twinOk && e.Next.Origin != e.Twin.Origin {
+				report(EndpointMismatch, EdgeElement, i, eIndex[e.Next], "")
+			}
+		}
+		if knownEdge(EdgeElement, i, e.Prev, "Prev") && e.Prev.Next != e {
+			report(NextPrevMismatch, EdgeElement, i, eIndex[e.Prev], "Prev.Next")
+		}
+	}
+
+	outDegree := make(map[*Vertex]int, len(dc.Vertices))
+	for _, e := range dc.HalfEdges {
+		outDegree[e.Origin]++
+	}
+	for i, v := range dc.Vertices {
+		if _, ok := eIndex[v.OutEdge]; !ok || v.OutEdge.Origin != v {
+			continue
+		}
+		// Walks broken by the issues above are not reported again.
+		n, e := 0, v.OutEdge
+		for {
+			n++
+			if e.Twin == nil || e.Twin.Next == nil || n > len(dc.HalfEdges) {
+				n = -1
+				break
+			}
+			e = e.Twin.Next
+			if e == v.OutEdge {
+				break
+			}
+			if e.Origin != v {
+				n = -1
+				break
+			}
+		}
+		if n >= 0 && n != outDegree[v] {
+			report(SplitVertex, VertexElement, i, -1,
+				strconv.Itoa(n)+" of "+strconv.Itoa(outDegree[v])+" edges reached")
+		}
+	}
+
+	for i, f := range dc.Faces {
+		if f.Outer == nil && len(f.Inner) == 0 {
+			if i != OUTER_FACE {
+				report(MissingField, FaceElement, i, -1, "Outer and Inner")
+			}
+			continue
+		}
+		for _, start := range f.Boundaries() {
+			if _, ok := eIndex[start]; !ok {
+				report(UnknownElement, FaceElement, i, -1, "boundary edge")
+				continue
+			}
+			e := start
+			for steps := 0; ; steps++ {
+				if e.Face != f {
+					report(FaceMismatch, EdgeElement, eIndex[e], i, "")
+				}
+				e = e.Next
+				if e == start {
+					break
+				}
+				if _, ok := eIndex[e]; !ok || steps >= len(dc.HalfEdges) {
+					report(OpenCycle, FaceElement, i, -1, "")
+					break
+				}
+			}
+		}
+	}
+
+	// Components are counted by joining each edge's endpoints.
+	parent := make([]int, len(dc.Vertices))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	components := len(dc.Vertices)
+	for _, e := range dc.HalfEdges {
+		if e.Origin == nil || e.Twin == nil || e.Twin.Origin == nil {
+			continue
+		}
+		a, ok1 := vIndex[e.Origin]
+		b, ok2 := vIndex[e.Twin.Origin]
+		if !ok1 || !ok2 {
+			continue
+		}
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[ra] = rb
+			components--
+		}
+	}
+	outerUsed := false
+	if len(dc.Faces) > OUTER_FACE {
+		outer := dc.Faces[OUTER_FACE]
+		outerUsed = outer.Outer != nil || len(outer.Inner) != 0
+		for _, e := range dc.HalfEdges {
+			if e.Face == outer {
+				outerUsed = true
+				break
+			}
+		}
+	}
+	v := len(dc.Vertices)
+	e := len(dc.HalfEdges) / 2
+	f := len(dc.Faces)
+	euler := v - e + f
+	valid := euler == 1+components
+	if !outerUsed {
+		euler--
+		f--
+		valid = euler <= 2*components && (2*components-euler)%2 == 0
+	}
+	if v > 0 && !valid {
+		report(EulerMismatch, NoElement, -1, -1,
+			"V="+strconv.Itoa(v)+" E="+strconv.Itoa(e)+" F="+strconv.Itoa(f)+
+				" C="+strconv.Itoa(components))
+	}
+	return issues
+}
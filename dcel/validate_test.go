@@ -0,0 +1,130 @@
+package dcel_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nylen/go-compgeo/dcel"
+	"github.com/nylen/go-compgeo/dcel/off"
+	"github.com/stretchr/testify/assert"
+)
+
+// hasIssue reports whether issues contains one of kind k
+// on the element of type et at index i.
+func hasIssue(issues []dcel.ValidationIssue, k dcel.IssueKind, et dcel.ElementType, i int) bool {
+	for _, vi := range issues {
+		if vi.Kind == k && vi.Element == et && vi.Index == i {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateValid(t *testing.T) {
+	assert.Empty(t, dcel.Rect(0, 0, 10, 10).Validate())
+	for i := 0; i < 20; i++ {
+		dc := dcel.Random2DDCELWithSeed(1000, 25, int64(i))
+		assert.Empty(t, dc.Validate())
+	}
+	assert.Empty(t, dcel.New().Validate())
+}
+
+func TestValidateOFF(t *testing.T) {
+	// A dcel written to and read from an OFF file should
+	// still be valid.
+	dc := dcel.Random2DDCELWithSeed(1000, 25, 1)
+	file := filepath.Join(t.TempDir(), "valid.off")
+	assert.Nil(t, off.Save(dc).WriteFile(file))
+	dc2, err := off.Load(file)
+	assert.Nil(t, err)
+	assert.Empty(t, dc2.Validate())
+
+	// Closed surfaces have no edges on the outer face.
+	dc3, err := off.Load(filepath.Join("..", "demo", "data", "cube.off"))
+	assert.Nil(t, err)
+	assert.Empty(t, dc3.Validate())
+}
+
+func TestValidateTwins(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges[2].Twin = dc.HalfEdges[4]
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.TwinMismatch, dcel.EdgeElement, 2))
+	assert.True(t, hasIssue(issues, dcel.TwinPairing, dcel.EdgeElement, 2))
+
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges[2], dc.HalfEdges[4] = dc.HalfEdges[4], dc.HalfEdges[2]
+	issues = dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.TwinPairing, dcel.EdgeElement, 2))
+	assert.True(t, hasIssue(issues, dcel.TwinPairing, dcel.EdgeElement, 4))
+	assert.False(t, hasIssue(issues, dcel.TwinMismatch, dcel.EdgeElement, 2))
+
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges = dc.HalfEdges[:7]
+	issues = dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.TwinPairing, dcel.EdgeElement, 6))
+}
+
+func TestValidateNextPrev(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	e := dc.HalfEdges[0]
+	e.Next = e.Next.Next
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.NextPrevMismatch, dcel.EdgeElement, 0))
+	assert.True(t, hasIssue(issues, dcel.EndpointMismatch, dcel.EdgeElement, 0))
+
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges[3].Prev = nil
+	issues = dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.MissingField, dcel.EdgeElement, 3))
+}
+
+func TestValidateFaces(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges[0].Face = dc.HalfEdges[1].Face
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.FaceMismatch, dcel.EdgeElement, 0))
+
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.HalfEdges[0].Face = dcel.NewFace()
+	issues = dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.UnknownElement, dcel.EdgeElement, 0))
+
+	dc = dcel.Rect(0, 0, 10, 10)
+	dc.Faces = append(dc.Faces, dcel.NewFace())
+	issues = dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.MissingField, dcel.FaceElement, 2))
+	assert.True(t, hasIssue(issues, dcel.EulerMismatch, dcel.NoElement, -1))
+}
+
+func TestValidateOutEdge(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	v := dc.Vertices[1]
+	v.OutEdge = v.OutEdge.Twin
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.OutEdgeOrigin, dcel.VertexElement, 1))
+}
+
+func TestValidateConnectVerts(t *testing.T) {
+	// Connecting two vertices splits a face, so a diagonal
+	// added without a new face breaks Euler's formula.
+	dc := dcel.Rect(0, 0, 10, 10)
+	dc.ConnectVerts(dc.Vertices[0], dc.Vertices[2], dc.Faces[1])
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.EulerMismatch, dcel.NoElement, -1))
+	assert.NotEmpty(t, issues[0].Error())
+}
+
+func TestValidateSplitVertex(t *testing.T) {
+	// The two triangles of bowtie.off meet at its first vertex,
+	// but the loader closes the outer boundary around each of
+	// them, so walking around that vertex reaches only the
+	// edges of one triangle.
+	dc, err := off.Load(filepath.Join("off", "testdata", "bowtie.off"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	issues := dc.Validate()
+	assert.True(t, hasIssue(issues, dcel.SplitVertex, dcel.VertexElement, 0))
+	assert.Len(t, issues, 1)
+}
@@ -212,15 +212,15 @@ func addFace(cID int, ev interface{}) int {
 					var err error
 					switch pointLocationMode {
 					case SLAB_DECOMPOSITION:
-						locator, err = slab.Decompose(&phd.DCEL, tree.RedBlack)
+						locator, err = slab.Decompose(&phd.DCEL, tree.RedBlack, visualizer)
 					case TRAPEZOID_MAP:
-						_, _, locator, err = trapezoid.TrapezoidalMap(&phd.DCEL)
+						_, _, locator, err = trapezoid.TrapezoidalMap(&phd.DCEL, visualizer)
 					case KIRKPATRICK_MONOTONE:
-						locator, err = kirkpatrick.TriangleTree(&phd.DCEL, kirkpatrick.MONOTONE)
+						locator, err = kirkpatrick.TriangleTree(&phd.DCEL, kirkpatrick.MONOTONE, visualizer)
 					case KIRKPATRICK_TRAPEZOID:
-						locator, err = kirkpatrick.TriangleTree(&phd.DCEL, kirkpatrick.TRAPEZOID)
+						locator, err = kirkpatrick.TriangleTree(&phd.DCEL, kirkpatrick.TRAPEZOID, visualizer)
 					case PLUMB_LINE:
-						locator = bruteForce.PlumbLine(&phd.DCEL)
+						locator = bruteForce.PlumbLine(&phd.DCEL, visualizer)
 					}
 					if err != nil {
 						fmt.Println(err)
@@ -70,6 +70,9 @@ var (
 	createdColor = color.RGBA{50, 140, 50, 255}
 
 	visSlider *Slider
+	// visualizer is given to each point locator built,
+	// and draws their steps while its channel is set.
+	visualizer = visualize.NewVisualizer()
 
 	randomize           = true
 	randomSplits        = 1
@@ -79,13 +82,13 @@ var (
 // InitScene is called whenever the scene 'demo' starts.
 // it creates the objects in our application.
 func InitScene(prevScene string, data interface{}) {
-	if visualize.VisualCh != nil {
-		close(visualize.VisualCh)
+	if visualizer.Ch != nil {
+		close(visualizer.Ch)
 		select {
 		case stopTickerCh <- true:
 		default:
 		}
-		visualize.VisualCh = nil
+		visualizer.Ch = nil
 	}
 	ticker = NewDynamicTicker()
 	loopDemo = true
@@ -278,8 +281,8 @@ func clear(no int, nothing interface{}) int {
 func visuals(no int, rt interface{}) int {
 	rate := rt.(time.Duration)
 	if rate != 0 {
-		if visualize.VisualCh == nil {
-			visualize.VisualCh = make(chan *visualize.Visual)
+		if visualizer.Ch == nil {
+			visualizer.Ch = make(chan *visualize.Visual)
 		}
 		select {
 		case stopTickerCh <- true:
@@ -296,7 +299,7 @@ func visuals(no int, rt interface{}) int {
 					if visual != nil {
 						render.UndrawAfter(visual, 100*time.Millisecond)
 					}
-					visual = <-visualize.VisualCh
+					visual = <-visualizer.Ch
 					if visual == nil {
 						return
 					}
@@ -309,13 +312,13 @@ func visuals(no int, rt interface{}) int {
 			}
 		}()
 	} else {
-		if visualize.VisualCh != nil {
-			close(visualize.VisualCh)
+		if visualizer.Ch != nil {
+			close(visualizer.Ch)
 			select {
 			case stopTickerCh <- true:
 			default:
 			}
-			visualize.VisualCh = nil
+			visualizer.Ch = nil
 		}
 	}
 	return 0
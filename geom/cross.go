@@ -9,7 +9,8 @@ func Dot2D(p1, p2 D2) float64 {
 }
 
 // Cross2D preforms the cross product on three points
-// in two dimensions.
+// in two dimensions. Its sign may be wrong when the points
+// are nearly colinear; Orient2D's sign is exact.
 func Cross2D(a, b, c D2) float64 {
 	return (b.X()-a.X())*(c.Y()-a.Y()) -
 		(b.Y()-a.Y())*(c.X()-a.X())
@@ -18,9 +19,10 @@ func Cross2D(a, b, c D2) float64 {
 // VertCross2D returns the cross product
 // corrected for verticality-- b and c
 // are organized such that left/rightness
-// checks can be made.
+// checks can be made. Its sign is exact, as
+// with Orient2D.
 func VertCross2D(a, b, c D2) float64 {
-	cp := Cross2D(a, b, c)
+	cp := Orient2D(a, b, c)
 	// If the first point of the line is above the second,
 	// the cross product will return a negative value for left.
 	if b.Y() > c.Y() {
@@ -32,16 +34,16 @@ func VertCross2D(a, b, c D2) float64 {
 // HzCross2D is equivalent to VertCross2D
 // for horizontal queries.
 func HzCross2D(a, b, c D2) float64 {
-	cp := Cross2D(a, b, c)
+	cp := Orient2D(a, b, c)
 	if b.X() > c.X() {
 		cp *= -1
 	}
 	return cp
 }
 
-// IsColinear returns whether the cross product reports 0.
+// IsColinear returns whether a, b and c lie exactly on one line.
 func IsColinear(a, b, c D2) bool {
-	return Cross2D(a, b, c) == 0
+	return Orient2D(a, b, c) == 0
 }
 
 // IsAbove returns whether a is above the line segment
@@ -100,7 +102,7 @@ func VerticalCompare(dp D2, e Spanning) search.CompareResult {
 	if p1.X() < p2.X() {
 		p1, p2 = p2, p1
 	}
-	s := Cross2D(p1, p2, dp)
+	s := Orient2D(p1, p2, dp)
 	if s == 0 {
 		return search.Equal
 	} else if s < 0 {
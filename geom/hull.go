@@ -0,0 +1,153 @@
+package geom
+
+import "sort"
+
+// ConvexHull returns the corners of the convex hull of pts,
+// found by Andrew's monotone chain, counter-clockwise from the
+// lowest of its leftmost points. Points along the hull's sides,
+// and repeated points, are left out. If pts all lie on one line,
+// only the ends of that line are returned.
+func ConvexHull(pts []D2) []D2 {
+	sorted := make([]D2, len(pts))
+	copy(sorted, pts)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X() != sorted[j].X() {
+			return sorted[i].X() < sorted[j].X()
+		}
+		return sorted[i].Y() < sorted[j].Y()
+	})
+	if len(sorted) < 3 {
+		return dedupeEnds(sorted)
+	}
+	hull := make([]D2, 0, 2*len(sorted))
+	// The lower hull runs left to right, and the upper
+	// hull back from right to left.
+	for _, p := range sorted {
+		for len(hull) >= 2 && Orient2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && Orient2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return dedupeEnds(hull[:len(hull)-1])
+}
+
+// PolylineHull returns the corners of the convex hull of the
+// simple polyline pts, found by Melkman's algorithm in linear
+// time, in the same order as ConvexHull. pts may be closed, as
+// a simple polygon's boundary, or open. If pts is not simple,
+// the result may not be its hull.
+func PolylineHull(pts []D2) []D2 {
+	if len(pts) == 0 {
+		return []D2{}
+	}
+	// Find the first point off the line
+	// through the first two distinct points.
+	start := 1
+	for start < len(pts) && pts[start].X() == pts[0].X() && pts[start].Y() == pts[0].Y() {
+		start++
+	}
+	k := start + 1
+	for k < len(pts) && Orient2D(pts[0], pts[start], pts[k]) == 0 {
+		k++
+	}
+	if k >= len(pts) {
+		// A simple polyline along one line only runs one way
+		// along it, so its ends are its first and last points.
+		return ConvexHull([]D2{pts[0], pts[len(pts)-1]})
+	}
+
+	// The deque d holds the hull counter-clockwise from its
+	// bottom, bot, to its top, top, which hold the same point.
+	d := make([]D2, 2*len(pts)+4)
+	bot, top := len(pts), len(pts)+3
+	a, b, c := pts[0], pts[k-1], pts[k]
+	if Orient2D(a, b, c) > 0 {
+		d[bot], d[bot+1], d[bot+2], d[bot+3] = c, a, b, c
+	} else {
+		d[bot], d[bot+1], d[bot+2], d[bot+3] = c, b, a, c
+	}
+	for _, v := range pts[k+1:] {
+		if Orient2D(d[top-1], d[top], v) > 0 && Orient2D(d[bot], d[bot+1], v) > 0 {
+			continue
+		}
+		for top-bot > 1 && Orient2D(d[top-1], d[top], v) <= 0 {
+			top--
+		}
+		top++
+		d[top] = v
+		for top-bot > 1 && Orient2D(d[bot], d[bot+1], v) <= 0 {
+			bot++
+		}
+		bot--
+		d[bot] = v
+	}
+	hull := dropColinear(d[bot:top])
+
+	// Start from the lowest of the leftmost points.
+	first := 0
+	for i, p := range hull {
+		q := hull[first]
+		if p.X() < q.X() || (p.X() == q.X() && p.Y() < q.Y()) {
+			first = i
+		}
+	}
+	return append(append([]D2{}, hull[first:]...), hull[:first]...)
+}
+
+// dropColinear drops points from hull, which runs
+// counter-clockwise around a convex polygon, which
+// lie on the line between the points around them.
+// Melkman's algorithm keeps points which join the
+// deque along a side of the hull, rather than at
+// a corner, so these are left out here, as they
+// are by ConvexHull.
+func dropColinear(hull []D2) []D2 {
+	out := make([]D2, 0, len(hull))
+	for _, p := range hull {
+		for len(out) >= 2 && Orient2D(out[len(out)-2], out[len(out)-1], p) == 0 {
+			out = out[:len(out)-1]
+		}
+		out = append(out, p)
+	}
+	// The points where the hull wraps around
+	// from its end to its start are checked last.
+	for len(out) >= 3 {
+		n := len(out)
+		if Orient2D(out[n-2], out[n-1], out[0]) == 0 {
+			out = out[:n-1]
+		} else if Orient2D(out[n-1], out[0], out[1]) == 0 {
+			out = out[1:]
+		} else {
+			break
+		}
+	}
+	return out
+}
+
+// dedupeEnds drops points from pts, which runs around a
+// hull, which repeat the point before them, along with a
+// last point which repeats the first.
+func dedupeEnds(pts []D2) []D2 {
+	out := []D2{}
+	for _, p := range pts {
+		if len(out) != 0 {
+			q := out[len(out)-1]
+			if p.X() == q.X() && p.Y() == q.Y() {
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	if len(out) > 1 && out[0].X() == out[len(out)-1].X() && out[0].Y() == out[len(out)-1].Y() {
+		out = out[:len(out)-1]
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package geom_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func pts(xys ...float64) []geom.D2 {
+	ps := make([]geom.D2, len(xys)/2)
+	for i := range ps {
+		ps[i] = geom.NewPoint(xys[2*i], xys[2*i+1], 0)
+	}
+	return ps
+}
+
+func TestConvexHullSquare(t *testing.T) {
+	// Corners, points along the sides, repeats
+	// and points inside
+	in := pts(2, 2, 0, 0, 4, 0, 2, 0, 4, 4, 1, 3, 0, 4, 0, 2, 4, 4, 3, 1)
+	want := pts(0, 0, 4, 0, 4, 4, 0, 4)
+	assert.Equal(t, want, geom.ConvexHull(in))
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	assert.Empty(t, geom.ConvexHull(nil))
+	assert.Empty(t, geom.PolylineHull(nil))
+	assert.Equal(t, pts(1, 1), geom.ConvexHull(pts(1, 1, 1, 1, 1, 1)))
+	assert.Equal(t, pts(1, 1), geom.PolylineHull(pts(1, 1, 1, 1, 1, 1)))
+	assert.Equal(t, pts(0, 0, 3, 3), geom.ConvexHull(pts(2, 2, 0, 0, 3, 3, 1, 1)))
+	assert.Equal(t, pts(0, 0, 3, 3), geom.PolylineHull(pts(3, 3, 2, 2, 1, 1, 0, 0)))
+}
+
+// starPolygon returns a random simple polygon, with
+// its points sorted by angle around its center.
+func starPolygon(rnd *rand.Rand, n int) []geom.D2 {
+	angles := make([]float64, n)
+	for i := range angles {
+		angles[i] = rnd.Float64() * 2 * math.Pi
+	}
+	sort.Float64s(angles)
+	poly := make([]geom.D2, n)
+	for i, a := range angles {
+		r := 10 + rnd.Float64()*90
+		poly[i] = geom.NewPoint(r*math.Cos(a), r*math.Sin(a), 0)
+	}
+	return poly
+}
+
+func TestPolylineHull(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		poly := starPolygon(rnd, 50)
+		// Start somewhere other than the first point,
+		// and close the polygon.
+		start := rnd.Intn(len(poly))
+		poly = append(append(poly[start:], poly[:start]...), poly[start])
+		hull := geom.ConvexHull(poly)
+		assert.Equal(t, hull, geom.PolylineHull(poly))
+		// Reversing the polygon gives the same hull.
+		rev := make([]geom.D2, len(poly))
+		for j, p := range poly {
+			rev[len(poly)-1-j] = p
+		}
+		assert.Equal(t, hull, geom.PolylineHull(rev))
+		for j, a := range hull {
+			b := hull[(j+1)%len(hull)]
+			for _, p := range poly {
+				assert.True(t, geom.Cross2D(a, b, p) >= 0)
+			}
+		}
+	}
+}
+
+// latticePolygon returns a random simple polygon with n or
+// fewer corners at integer points from -r to r, so that many
+// of its points line up. Its points are sorted by angle
+// around a point off the lattice, and then by distance from
+// it, so that points along one ray are joined in order.
+func latticePolygon(rnd *rand.Rand, n, r int) []geom.D2 {
+	const cx, cy = 0.25, 0.125
+	seen := map[[2]int]bool{}
+	poly := []geom.D2{}
+	for len(poly) < n {
+		x, y := rnd.Intn(2*r+1)-r, rnd.Intn(2*r+1)-r
+		if !seen[[2]int{x, y}] {
+			seen[[2]int{x, y}] = true
+			poly = append(poly, geom.NewPoint(float64(x), float64(y), 0))
+		}
+	}
+	angle := func(p geom.D2) float64 {
+		return math.Atan2(p.Y()-cy, p.X()-cx)
+	}
+	sort.Slice(poly, func(i, j int) bool {
+		a, b := angle(poly[i]), angle(poly[j])
+		if a != b {
+			return a < b
+		}
+		return math.Hypot(poly[i].X()-cx, poly[i].Y()-cy) < math.Hypot(poly[j].X()-cx, poly[j].Y()-cy)
+	})
+	// Joining points in angle order only gives a simple
+	// polygon if no gap between them is wider than π.
+	for i, p := range poly {
+		gap := angle(poly[(i+1)%len(poly)]) - angle(p)
+		if gap <= 0 {
+			gap += 2 * math.Pi
+		}
+		if gap >= math.Pi {
+			return latticePolygon(rnd, n, r)
+		}
+	}
+	return poly
+}
+
+func TestPolylineHullColinear(t *testing.T) {
+	// (-40, -45) lies on the side of the hull
+	// from (-58, -39) to (62, -79).
+	poly := pts(-9, -39, 62, -79, 44, -41, 10, 0, 97, 24, 33, 50, 1, 50,
+		-29, 96, -69, 11, -37, -15, -58, -39, -24, -17, -40, -45)
+	assert.Equal(t, geom.ConvexHull(poly), geom.PolylineHull(poly))
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		poly := latticePolygon(rnd, 4+rnd.Intn(20), 3+rnd.Intn(8))
+		start := rnd.Intn(len(poly))
+		poly = append(poly[start:], poly[:start]...)
+		hull := geom.ConvexHull(poly)
+		if !assert.Equal(t, hull, geom.PolylineHull(poly), "%v", poly) {
+			continue
+		}
+		closed := append(append([]geom.D2{}, poly...), poly[0])
+		assert.Equal(t, hull, geom.PolylineHull(closed), "%v", closed)
+	}
+}
@@ -0,0 +1,79 @@
+// Package intersect finds the points at which sets of segments
+// meet, by a Bentley-Ottmann line sweep.
+package intersect
+
+import (
+	"sort"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search"
+	"github.com/nylen/go-compgeo/search/tree"
+)
+
+// An Intersection is a point at which the sweep over a set of
+// segments stopped, with the indices of every segment which
+// touches it, in increasing order.
+type Intersection struct {
+	geom.Point
+	Segments []int
+}
+
+// Find returns every point at which two or more of segs meet,
+// from left to right. This includes endpoints which segments
+// share, and points where one segment ends on another. Where
+// collinear segments overlap, the ends of each overlap are
+// reported. Only the x and y values of segs are considered.
+func Find(segs []geom.FullEdge) ([]Intersection, error) {
+	pts, err := Sweep(segs)
+	if err != nil {
+		return nil, err
+	}
+	found := []Intersection{}
+	for _, in := range pts {
+		if len(in.Segments) > 1 {
+			found = append(found, in)
+		}
+	}
+	return found, nil
+}
+
+// Sweep runs a line sweep from left to right over segs, returning
+// every endpoint and intersection of segs in sweep order, as found
+// by Before, with the segments touching each. Points within ε of
+// each other are treated as one point. Segments of zero length are
+// ignored.
+func Sweep(segs []geom.FullEdge) ([]Intersection, error) {
+	sw := &sweeper{
+		events: tree.New(tree.RedBlack),
+		status: tree.New(tree.RedBlack),
+		st:     new(sweepStatus),
+	}
+	for i, fe := range segs {
+		l, r := fe[0], fe[1]
+		if Before(r, l) {
+			l, r = r, l
+		}
+		if !Before(l, r) {
+			continue
+		}
+		s := &sweepSeg{i, l, r}
+		ev := sw.addEvent(l)
+		ev.starts = append(ev.starts, s)
+		sw.addEvent(r)
+	}
+	pts := []Intersection{}
+	for sw.events.Size() != 0 {
+		k, v := sw.events.SearchUp(search.NegativeInf{}, 0)
+		ev := v.(*sweepEvent)
+		if err := sw.events.Delete(sweepNode{k, ev}); err != nil {
+			return nil, err
+		}
+		segs, err := sw.handle(ev)
+		if err != nil {
+			return nil, err
+		}
+		sort.Ints(segs)
+		pts = append(pts, Intersection{ev.pt, segs})
+	}
+	return pts, nil
+}
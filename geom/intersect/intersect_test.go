@@ -0,0 +1,155 @@
+package intersect_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/geom/intersect"
+	"github.com/stretchr/testify/assert"
+)
+
+func seg(x1, y1, x2, y2 float64) geom.FullEdge {
+	return geom.FullEdge{geom.NewPoint(x1, y1, 0), geom.NewPoint(x2, y2, 0)}
+}
+
+// testFind checks that Find reports exactly the
+// points in want, with the segments meeting at each.
+func testFind(t *testing.T, segs []geom.FullEdge, want []intersect.Intersection) {
+	found, err := intersect.Find(segs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if !assert.Equal(t, len(want), len(found)) {
+		t.Log(found)
+		return
+	}
+	for i, in := range found {
+		assert.True(t, in.Point.Eq(want[i].Point), in.Point.String())
+		assert.Equal(t, want[i].Segments, in.Segments)
+	}
+}
+
+func TestFindCross(t *testing.T) {
+	testFind(t, []geom.FullEdge{
+		seg(0, 0, 10, 10),
+		seg(0, 10, 10, 0),
+		seg(0, 20, 10, 20),
+	}, []intersect.Intersection{
+		{geom.NewPoint(5, 5, 0), []int{0, 1}},
+	})
+}
+
+func TestFindEndpoints(t *testing.T) {
+	testFind(t, []geom.FullEdge{
+		seg(0, 0, 5, 5),
+		seg(10, 0, 5, 5),
+		seg(5, 5, 5, 10),
+		// An endpoint on another segment
+		seg(2, 0, 2, 2),
+	}, []intersect.Intersection{
+		{geom.NewPoint(2, 2, 0), []int{0, 3}},
+		{geom.NewPoint(5, 5, 0), []int{0, 1, 2}},
+	})
+}
+
+func TestFindOverlap(t *testing.T) {
+	testFind(t, []geom.FullEdge{
+		seg(0, 0, 6, 0),
+		seg(10, 0, 4, 0),
+		seg(1, 1, 3, 3),
+		seg(2, 2, 5, 5),
+	}, []intersect.Intersection{
+		{geom.NewPoint(2, 2, 0), []int{2, 3}},
+		{geom.NewPoint(3, 3, 0), []int{2, 3}},
+		{geom.NewPoint(4, 0, 0), []int{0, 1}},
+		{geom.NewPoint(6, 0, 0), []int{0, 1}},
+	})
+}
+
+func TestFindVertical(t *testing.T) {
+	testFind(t, []geom.FullEdge{
+		seg(5, -5, 5, 3.5),
+		seg(0, 0, 10, 0),
+		seg(0, 2, 10, 4),
+		seg(5, 4, 5, 8),
+		seg(5, 7, 5, 9),
+	}, []intersect.Intersection{
+		{geom.NewPoint(5, 0, 0), []int{0, 1}},
+		{geom.NewPoint(5, 3, 0), []int{0, 2}},
+		{geom.NewPoint(5, 7, 0), []int{3, 4}},
+		{geom.NewPoint(5, 8, 0), []int{3, 4}},
+	})
+}
+
+func TestSweepOrder(t *testing.T) {
+	segs := []geom.FullEdge{
+		seg(3, 0, 0, 3),
+		seg(0, 0, 3, 3),
+		seg(1, 5, 1, 4),
+		// Zero length segments are ignored
+		seg(2, 2, 2, 2),
+	}
+	pts, err := intersect.Sweep(segs)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(pts))
+	for i := 1; i < len(pts); i++ {
+		assert.True(t, intersect.Before(pts[i-1], pts[i]))
+	}
+}
+
+// touch reports whether the closed segments a and b share any
+// point, for segments with small integer coordinates.
+func touch(a, b geom.FullEdge) bool {
+	within := func(p, q, r geom.Point) bool {
+		return r.X() >= math.Min(p.X(), q.X()) && r.X() <= math.Max(p.X(), q.X()) &&
+			r.Y() >= math.Min(p.Y(), q.Y()) && r.Y() <= math.Max(p.Y(), q.Y())
+	}
+	d1 := geom.Cross2D(b[0], b[1], a[0])
+	d2 := geom.Cross2D(b[0], b[1], a[1])
+	d3 := geom.Cross2D(a[0], a[1], b[0])
+	d4 := geom.Cross2D(a[0], a[1], b[1])
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && within(b[0], b[1], a[0])) ||
+		(d2 == 0 && within(b[0], b[1], a[1])) ||
+		(d3 == 0 && within(a[0], a[1], b[0])) ||
+		(d4 == 0 && within(a[0], a[1], b[1]))
+}
+
+func TestFindRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 50; n++ {
+		// Coordinates on a small grid make shared endpoints,
+		// overlaps and vertical segments common.
+		segs := make([]geom.FullEdge, 20)
+		for i := range segs {
+			segs[i] = seg(float64(rng.Intn(8)), float64(rng.Intn(8)),
+				float64(rng.Intn(8)), float64(rng.Intn(8)))
+			if segs[i][0] == segs[i][1] {
+				segs[i][1][0]++
+			}
+		}
+		found, err := intersect.Find(segs)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		pairs := make(map[[2]int]bool)
+		for _, in := range found {
+			for i, a := range in.Segments {
+				for _, b := range in.Segments[i+1:] {
+					pairs[[2]int{a, b}] = true
+				}
+			}
+		}
+		for a := range segs {
+			for b := a + 1; b < len(segs); b++ {
+				assert.Equal(t, touch(segs[a], segs[b]), pairs[[2]int{a, b}],
+					segs[a], segs[b])
+			}
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package intersect
+
+import (
+	"errors"
+	"math"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/search"
+)
+
+type sweeper struct {
+	events search.Dynamic
+	status search.Dynamic
+	st     *sweepStatus
+}
+
+// handle processes the event ev, returning the indices of the
+// segments which touch its point.
+func (sw *sweeper) handle(ev *sweepEvent) ([]int, error) {
+	p := ev.pt
+	sw.st.p = p
+	// Segments which touch p are reordered by removing
+	// them as they were ordered just left of p and adding
+	// those which continue past p as they are ordered
+	// just right of it.
+	sw.st.before = true
+	through := sw.through(p)
+	segs := make([]int, 0, len(through)+len(ev.starts))
+	for _, s := range ev.starts {
+		segs = append(segs, s.id)
+	}
+	for _, s := range through {
+		segs = append(segs, s.id)
+	}
+
+	for _, s := range through {
+		if err := sw.status.Delete(sweepNode{statusKey{s, sw.st}, s}); err != nil {
+			return nil, errors.New("Segments out of order at " + p.String())
+		}
+	}
+	sw.st.before = false
+	passing := append([]*sweepSeg{}, ev.starts...)
+	for _, s := range through {
+		if !pointEq(s.right, p) {
+			passing = append(passing, s)
+		}
+	}
+	for _, s := range passing {
+		sw.status.Insert(sweepNode{statusKey{s, sw.st}, s})
+	}
+
+	if len(passing) == 0 {
+		sw.check(sw.below(p), sw.above(p))
+		return segs, nil
+	}
+	lowest := sw.above(p)
+	highest := lowest
+	for _, s := range sw.through(p) {
+		highest = s
+	}
+	sw.check(sw.neighbor(lowest, false), lowest)
+	sw.check(highest, sw.neighbor(highest, true))
+	return segs, nil
+}
+
+// through returns the segments in the status which
+// touch p, from bottom to top.
+func (sw *sweeper) through(p geom.Point) []*sweepSeg {
+	segs := []*sweepSeg{}
+	for s := sw.above(p); s != nil && s.y(sw.st) == p.Y(); s = sw.neighbor(s, true) {
+		segs = append(segs, s)
+	}
+	return segs
+}
+
+// above returns the lowest segment in the status
+// which passes through or above p.
+func (sw *sweeper) above(p geom.Point) *sweepSeg {
+	k, _ := sw.status.SearchUp(sweepProbe{p}, 0)
+	if k == nil {
+		return nil
+	}
+	s := k.(statusKey).sweepSeg
+	if s.y(sw.st) < p.Y() {
+		return nil
+	}
+	return s
+}
+
+// below returns the highest segment in
+// the status which passes below p.
+func (sw *sweeper) below(p geom.Point) *sweepSeg {
+	k, _ := sw.status.SearchDown(sweepProbe{p}, 0)
+	if k == nil {
+		return nil
+	}
+	s := k.(statusKey).sweepSeg
+	if s.y(sw.st) >= p.Y() {
+		return nil
+	}
+	return s
+}
+
+// neighbor returns the segment just above s in
+// the status if up is true, or just below it
+// otherwise. If there is none, it returns nil.
+func (sw *sweeper) neighbor(s *sweepSeg, up bool) *sweepSeg {
+	if s == nil {
+		return nil
+	}
+	var k search.Comparable
+	if up {
+		k, _ = sw.status.SearchUp(statusKey{s, sw.st}, 1)
+	} else {
+		k, _ = sw.status.SearchDown(statusKey{s, sw.st}, 1)
+	}
+	if k == nil || k.(statusKey).sweepSeg == s {
+		return nil
+	}
+	return k.(statusKey).sweepSeg
+}
+
+// check adds an event where a and b cross,
+// if they cross to the right of the sweep.
+func (sw *sweeper) check(a, b *sweepSeg) {
+	if a == nil || b == nil {
+		return
+	}
+	if q, ok := a.intersect(b); ok && Before(sw.st.p, q) {
+		sw.addEvent(q)
+	}
+}
+
+// addEvent returns the event at p, adding it
+// to the event queue if it is not yet there.
+func (sw *sweeper) addEvent(p geom.Point) *sweepEvent {
+	k := eventKey{p}
+	if ok, v := sw.events.Search(k); ok {
+		return v.(*sweepEvent)
+	}
+	ev := &sweepEvent{pt: p}
+	sw.events.Insert(sweepNode{k, ev})
+	return ev
+}
+
+// A sweepStatus is the position of the sweep line,
+// shared by every key in the status structure.
+type sweepStatus struct {
+	p geom.Point
+	// before is set when segments are ordered as
+	// they are just left of p, rather than just
+	// right of it.
+	before bool
+}
+
+// A sweepSeg is a segment whose left
+// point is lexicographically lesser.
+type sweepSeg struct {
+	id          int
+	left, right geom.Point
+}
+
+func (s *sweepSeg) Equals(e search.Equalable) bool {
+	return s == e
+}
+
+// y returns the height of s on the sweep line. Vertical
+// segments are at the height of the sweep point, clamped
+// to their extent.
+func (s *sweepSeg) y(st *sweepStatus) float64 {
+	l, r := s.left, s.right
+	var y float64
+	if geom.F64eq(l.X(), r.X()) {
+		y = math.Max(l.Y(), math.Min(st.p.Y(), r.Y()))
+	} else {
+		y = l.Y() + (r.Y()-l.Y())*(st.p.X()-l.X())/(r.X()-l.X())
+	}
+	// Segments passing within ε of the sweep
+	// point are treated as passing through it.
+	if geom.F64eq(y, st.p.Y()) {
+		return st.p.Y()
+	}
+	return y
+}
+
+// turn returns the cross product of the directions of s
+// and s2, positive if s2 is steeper, or zero if they
+// are parallel.
+func (s *sweepSeg) turn(s2 *sweepSeg) float64 {
+	ax, ay := s.right.X()-s.left.X(), s.right.Y()-s.left.Y()
+	bx, by := s2.right.X()-s2.left.X(), s2.right.Y()-s2.left.Y()
+	c := ax*by - ay*bx
+	if math.Abs(c) <= 1e-9*math.Hypot(ax, ay)*math.Hypot(bx, by) {
+		return 0
+	}
+	return c
+}
+
+// intersect returns the point where s and s2 cross, if
+// they cross at a single point. Collinear segments never
+// cross, as where they overlap begins and ends at their
+// endpoints.
+func (s *sweepSeg) intersect(s2 *sweepSeg) (geom.Point, bool) {
+	d := s.turn(s2)
+	if d == 0 {
+		return geom.Point{}, false
+	}
+	rx, ry := s.right.X()-s.left.X(), s.right.Y()-s.left.Y()
+	sx, sy := s2.right.X()-s2.left.X(), s2.right.Y()-s2.left.Y()
+	qx, qy := s2.left.X()-s.left.X(), s2.left.Y()-s.left.Y()
+	t := (qx*sy - qy*sx) / d
+	u := (qx*ry - qy*rx) / d
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return geom.Point{}, false
+	}
+	return geom.NewPoint(s.left.X()+t*rx, s.left.Y()+t*ry, 0), true
+}
+
+// A statusKey orders segments on the sweep line
+// from bottom to top.
+type statusKey struct {
+	*sweepSeg
+	st *sweepStatus
+}
+
+func (sk statusKey) Compare(i interface{}) search.CompareResult {
+	switch k := i.(type) {
+	case statusKey:
+		if sk.sweepSeg == k.sweepSeg {
+			return search.Equal
+		}
+		y1, y2 := sk.y(sk.st), k.y(sk.st)
+		if y1 != y2 {
+			if y1 < y2 {
+				return search.Less
+			}
+			return search.Greater
+		}
+		// The segments meet on the sweep line. Right of
+		// that point, the steeper segment is above.
+		if c := sk.turn(k.sweepSeg); c != 0 {
+			if (c > 0) != sk.st.before {
+				return search.Less
+			}
+			return search.Greater
+		}
+		if sk.id < k.id {
+			return search.Less
+		}
+		return search.Greater
+	case sweepProbe:
+		l, r := sk.left, sk.right
+		if sk.y(sk.st) == k.Y() {
+			return search.Greater
+		}
+		if geom.F64eq(l.X(), r.X()) {
+			if sk.y(sk.st) < k.Y() {
+				return search.Less
+			}
+			return search.Greater
+		}
+		// Segments below the probe point are lesser.
+		if geom.VerticalCompare(k, geom.FullEdge{l, r}) == search.Less {
+			return search.Less
+		}
+		return search.Greater
+	}
+	return search.Invalid
+}
+
+// A sweepProbe searches the status for the
+// segments at or above a point on the sweep line.
+type sweepProbe struct {
+	geom.Point
+}
+
+// A sweepEvent is a point the sweep stops at, with
+// the segments whose left point it is.
+type sweepEvent struct {
+	pt     geom.Point
+	starts []*sweepSeg
+}
+
+func (ev *sweepEvent) Equals(e search.Equalable) bool {
+	return ev == e
+}
+
+// An eventKey orders events lexicographically.
+type eventKey struct {
+	geom.Point
+}
+
+func (ek eventKey) Compare(i interface{}) search.CompareResult {
+	switch k := i.(type) {
+	case eventKey:
+		if Before(ek.Point, k.Point) {
+			return search.Less
+		}
+		if Before(k.Point, ek.Point) {
+			return search.Greater
+		}
+		return search.Equal
+	case search.NegativeInf:
+		return search.Greater
+	}
+	return search.Invalid
+}
+
+type sweepNode struct {
+	k search.Comparable
+	v search.Equalable
+}
+
+func (sn sweepNode) Key() search.Comparable {
+	return sn.k
+}
+
+func (sn sweepNode) Val() search.Equalable {
+	return sn.v
+}
+
+// Before reports whether the sweep reaches a before b: whether
+// a is left of b, or below it if they share an x value. Values
+// within ε of each other are treated as equal.
+func Before(a, b geom.D2) bool {
+	if !geom.F64eq(a.X(), b.X()) {
+		return a.X() < b.X()
+	}
+	if !geom.F64eq(a.Y(), b.Y()) {
+		return a.Y() < b.Y()
+	}
+	return false
+}
+
+// pointEq reports whether a and b are within ε.
+func pointEq(a, b geom.D2) bool {
+	return !Before(a, b) && !Before(b, a)
+}
@@ -0,0 +1,125 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+)
+
+// The error bounds below are from Shewchuk's "Adaptive Precision
+// Floating-Point Arithmetic and Fast Robust Geometric Predicates".
+// If a determinant computed in float64 is further from zero than
+// its bound, its sign is certain. epsilon is half of the distance
+// from 1 to the next float64.
+const (
+	epsilon     = 1.0 / (1 << 53)
+	ccwErrBound = (3 + 16*epsilon) * epsilon
+	iccErrBound = (10 + 96*epsilon) * epsilon
+)
+
+// Orient2D returns a positive value if a, b and c run counter-
+// clockwise, a negative value if they run clockwise, and zero if
+// they lie on one line. Its value is that of Cross2D, but unlike
+// Cross2D, its sign is always exact: when rounding could make
+// the sign of Cross2D wrong, the determinant is found exactly.
+func Orient2D(a, b, c D2) float64 {
+	detLeft := (a.X() - c.X()) * (b.Y() - c.Y())
+	detRight := (a.Y() - c.Y()) * (b.X() - c.X())
+	det := detLeft - detRight
+	var detSum float64
+	if detLeft > 0 {
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	} else if detLeft < 0 {
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	} else {
+		return det
+	}
+	if bound := ccwErrBound * detSum; det >= bound || -det >= bound {
+		return det
+	}
+	r := rats(a.X(), a.Y(), b.X(), b.Y(), c.X(), c.Y())
+	if r == nil {
+		return det
+	}
+	acx, acy := sub(r[0], r[4]), sub(r[1], r[5])
+	bcx, bcy := sub(r[2], r[4]), sub(r[3], r[5])
+	return toFloat(sub(mul(acx, bcy), mul(acy, bcx)))
+}
+
+// InCircle returns a positive value if d lies inside the circle
+// through a, b and c, a negative value if it lies outside it, and
+// zero if it lies on it, where a, b and c run counter-clockwise.
+// If they run clockwise, the sign is reversed. As with Orient2D,
+// the sign is always exact.
+func InCircle(a, b, c, d D2) float64 {
+	adx, ady := a.X()-d.X(), a.Y()-d.Y()
+	bdx, bdy := b.X()-d.X(), b.Y()-d.Y()
+	cdx, cdy := c.X()-d.X(), c.Y()-d.Y()
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	aLift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	bLift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	cLift := cdx*cdx + cdy*cdy
+
+	det := aLift*(bdxcdy-cdxbdy) +
+		bLift*(cdxady-adxcdy) +
+		cLift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*aLift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*bLift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*cLift
+	if bound := iccErrBound * permanent; det > bound || -det > bound {
+		return det
+	}
+	r := rats(a.X(), a.Y(), b.X(), b.Y(), c.X(), c.Y(), d.X(), d.Y())
+	if r == nil {
+		return det
+	}
+	ex, ey := sub(r[0], r[6]), sub(r[1], r[7])
+	fx, fy := sub(r[2], r[6]), sub(r[3], r[7])
+	gx, gy := sub(r[4], r[6]), sub(r[5], r[7])
+	lift := func(x, y *big.Rat) *big.Rat {
+		return new(big.Rat).Add(mul(x, x), mul(y, y))
+	}
+	exact := mul(lift(ex, ey), sub(mul(fx, gy), mul(gx, fy)))
+	exact.Add(exact, mul(lift(fx, fy), sub(mul(gx, ey), mul(ex, gy))))
+	exact.Add(exact, mul(lift(gx, gy), sub(mul(ex, fy), mul(fx, ey))))
+	return toFloat(exact)
+}
+
+// rats returns fs as exact rationals, or nil if any
+// of fs is infinite or not a number.
+func rats(fs ...float64) []*big.Rat {
+	rs := make([]*big.Rat, len(fs))
+	for i, f := range fs {
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil
+		}
+		rs[i] = new(big.Rat).SetFloat64(f)
+	}
+	return rs
+}
+
+func sub(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(a, b)
+}
+
+func mul(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
+
+// toFloat returns the float64 nearest r, keeping
+// its sign if it is too small to represent.
+func toFloat(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	if f == 0 && r.Sign() != 0 {
+		return math.Copysign(math.SmallestNonzeroFloat64, float64(r.Sign()))
+	}
+	return f
+}
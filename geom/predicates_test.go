@@ -0,0 +1,89 @@
+package geom_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func rat(f float64) *big.Rat {
+	return new(big.Rat).SetFloat64(f)
+}
+
+// exactOrient returns the sign of the orientation of a, b and c.
+func exactOrient(a, b, c geom.D2) int {
+	l := new(big.Rat).Mul(new(big.Rat).Sub(rat(b.X()), rat(a.X())),
+		new(big.Rat).Sub(rat(c.Y()), rat(a.Y())))
+	r := new(big.Rat).Mul(new(big.Rat).Sub(rat(b.Y()), rat(a.Y())),
+		new(big.Rat).Sub(rat(c.X()), rat(a.X())))
+	return l.Cmp(r)
+}
+
+func sign(f float64) int {
+	if f > 0 {
+		return 1
+	} else if f < 0 {
+		return -1
+	}
+	return 0
+}
+
+func TestOrient2DNearlyColinear(t *testing.T) {
+	// Points a few ulps from (.5, .5), measured against a line
+	// through it, are where Cross2D's rounding misleads it.
+	b, c := geom.NewPoint(12, 12, 0), geom.NewPoint(24, 24, 0)
+	wrong := 0
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			a := geom.NewPoint(.5+float64(i)*math.Pow(2, -53), .5+float64(j)*math.Pow(2, -53), 0)
+			want := exactOrient(a, b, c)
+			assert.Equal(t, want, sign(geom.Orient2D(a, b, c)), "%v", a)
+			assert.Equal(t, -want, sign(geom.Orient2D(b, a, c)), "%v", a)
+			assert.Equal(t, want, sign(geom.Orient2D(b, c, a)), "%v", a)
+			if sign(geom.Cross2D(a, b, c)) != want {
+				wrong++
+			}
+		}
+	}
+	assert.NotZero(t, wrong)
+}
+
+func TestOrient2DRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a := geom.NewPoint(rnd.Float64()*100, rnd.Float64()*100, 0)
+		b := geom.NewPoint(rnd.Float64()*100, rnd.Float64()*100, 0)
+		c := geom.NewPoint(rnd.Float64()*100, rnd.Float64()*100, 0)
+		assert.Equal(t, exactOrient(a, b, c), sign(geom.Orient2D(a, b, c)))
+		// A point along ab, which is rarely exactly on it.
+		s := rnd.Float64()
+		d := geom.NewPoint(a.X()+s*(b.X()-a.X()), a.Y()+s*(b.Y()-a.Y()), 0)
+		assert.Equal(t, exactOrient(a, b, d), sign(geom.Orient2D(a, b, d)))
+	}
+	assert.Equal(t, 0.0, geom.Orient2D(geom.NewPoint(1, 1, 0),
+		geom.NewPoint(2, 3, 0), geom.NewPoint(3, 5, 0)))
+}
+
+func TestInCircle(t *testing.T) {
+	a, b, c := geom.NewPoint(1, 0, 0), geom.NewPoint(0, 1, 0), geom.NewPoint(-1, 0, 0)
+	assert.Equal(t, 0.0, geom.InCircle(a, b, c, geom.NewPoint(0, -1, 0)))
+	assert.True(t, geom.InCircle(a, b, c, geom.NewPoint(0, 0, 0)) > 0)
+	assert.True(t, geom.InCircle(a, b, c, geom.NewPoint(2, 2, 0)) < 0)
+	assert.True(t, geom.InCircle(c, b, a, geom.NewPoint(0, 0, 0)) < 0)
+	// One ulp inside or outside the circle.
+	below := math.Nextafter(-1, 0)
+	assert.True(t, geom.InCircle(a, b, c, geom.NewPoint(0, below, 0)) > 0)
+	above := math.Nextafter(-1, -2)
+	assert.True(t, geom.InCircle(a, b, c, geom.NewPoint(0, above, 0)) < 0)
+	// A large, offset circle.
+	off := 1e8
+	a2 := geom.NewPoint(off+3, off, 0)
+	b2 := geom.NewPoint(off, off+3, 0)
+	c2 := geom.NewPoint(off-3, off, 0)
+	assert.Equal(t, 0.0, geom.InCircle(a2, b2, c2, geom.NewPoint(off, off-3, 0)))
+	assert.True(t, geom.InCircle(a2, b2, c2, geom.NewPoint(off, math.Nextafter(off-3, off), 0)) > 0)
+}
@@ -0,0 +1,153 @@
+package tree
+
+import "errors"
+
+var (
+	// AaFnSet performs AA skews and splits on inserts
+	// and deletes, and does nothing on lookups.
+	AaFnSet = &FnSet{
+		InsertFn:  aaInsert,
+		DeleteFn:  aaDelete,
+		SearchFn:  nopNode,
+		PayloadFn: aaPayload,
+	}
+)
+
+// An AA node's payload is its level. New nodes
+// are leaves, at level one.
+func aaPayload() interface{} {
+	return 1
+}
+
+func (n *node) level() int {
+	if n == nil {
+		return 0
+	}
+	return n.payload.(int)
+}
+
+// AAValid returns whether the given BST is a valid AA tree
+func AAValid(bst *BST) (bool, error) {
+	return bst.root.AAValid()
+}
+
+// AAValid returns whether the given node is a valid AA subtree.
+func (n *node) AAValid() (bool, error) {
+	if n == nil {
+		return true, nil
+	}
+	if _, ok := n.payload.(int); !ok {
+		return false, errors.New("A node did not have a level")
+	}
+	if n.left == nil && n.right == nil && n.level() != 1 {
+		return false, errors.New("A leaf was not at level one")
+	}
+	if n.left.level() != n.level()-1 {
+		return false, errors.New("A left child was not one level below its parent")
+	}
+	if n.right.level() != n.level() && n.right.level() != n.level()-1 {
+		return false, errors.New("A right child was not at or one below its parent's level")
+	}
+	if n.right != nil && n.right.right.level() >= n.level() {
+		return false, errors.New("A right grandchild was not below its grandparent's level")
+	}
+	if n.level() > 1 && (n.left == nil || n.right == nil) {
+		return false, errors.New("A node above level one did not have two children")
+	}
+	b, err := n.left.AAValid()
+	if !b {
+		return b, err
+	}
+	return n.right.AAValid()
+}
+
+// skew removes a left horizontal link below n,
+// returning the new root of n's subtree.
+func (n *node) skew() *node {
+	if n == nil || n.left == nil || n.left.level() != n.level() {
+		return n
+	}
+	l := n.left
+	n.rightRotate()
+	return l
+}
+
+// split removes two consecutive right horizontal links
+// below n, returning the new root of n's subtree.
+func (n *node) split() *node {
+	if n == nil || n.right == nil || n.right.right.level() != n.level() {
+		return n
+	}
+	r := n.right
+	n.leftRotate()
+	r.payload = r.level() + 1
+	return r
+}
+
+func aaInsert(n *node) *node {
+	for {
+		n = n.skew().split()
+		if n.parent == nil {
+			return n
+		}
+		n = n.parent
+	}
+}
+
+func aaDelete(n *node) (newRoot *node) {
+	// p is the lowest node whose subtree lost a node
+	var p *node
+	if n.left == nil || n.right == nil {
+		c := n.left
+		if c == nil {
+			c = n.right
+		}
+		p = n.parent
+		newRoot = n.parentReplace(c)
+	} else {
+		// Replace n with its successor
+		n2 := n.right.minKey()
+		if n2.parent == n {
+			p = n2
+		} else {
+			p = n2.parent
+			n2.parentReplace(n2.right)
+			n2.right = n.right
+			n2.right.parent = n2
+		}
+		newRoot = n.parentReplace(n2)
+		n2.left = n.left
+		n2.left.parent = n2
+		n2.touch()
+		n2.payload = n.payload
+	}
+	for p != nil {
+		should := p.left.level()
+		if p.right.level() < should {
+			should = p.right.level()
+		}
+		should++
+		if should < p.level() {
+			p.payload = should
+			if should < p.right.level() {
+				p.right.payload = should
+			}
+		}
+		p = p.skew()
+		if p.right != nil {
+			p.right.skew()
+			if p.right.right != nil {
+				p.right.right.skew()
+			}
+		}
+		p = p.split()
+		if p.right != nil {
+			p.right.split()
+		}
+		if p.parent == nil {
+			newRoot = p
+		}
+		p = p.parent
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package tree
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAADefinedInput1(t *testing.T) {
+	testDefinedInput(t, AA, AAValid)
+}
+
+func TestAARandomInput(t *testing.T) {
+	testRandomInput(t, AA, AAValid)
+}
+
+func TestAASortedInput(t *testing.T) {
+	testSortedInput(t, AA, AAValid)
+}
+
+func TestAALevels(t *testing.T) {
+	testRandomOps(t, AA, func(t *testing.T, root *node) {
+		nodes := root.nodes()
+		for _, n := range nodes {
+			lv, ok := n.payload.(int)
+			if !assert.True(t, ok, "key %v has no level", n.key) {
+				return
+			}
+			if n.left == nil && n.right == nil {
+				assert.Equal(t, 1, lv, "leaf %v", n.key)
+			}
+			if n.left != nil {
+				assert.Equal(t, lv-1, n.left.level(), "left child of %v", n.key)
+			} else if lv > 1 {
+				t.Errorf("key %v is at level %d with no left child", n.key, lv)
+			}
+			if n.right != nil {
+				rl := n.right.level()
+				assert.True(t, rl == lv || rl == lv-1, "right child of %v", n.key)
+				if n.right.right != nil {
+					assert.True(t, n.right.right.level() < lv,
+						"right grandchild of %v is not below it", n.key)
+				}
+			} else if lv > 1 {
+				t.Errorf("key %v is at level %d with no right child", n.key, lv)
+			}
+		}
+		// Levels fall by one on every left link and at least
+		// every other right link, so the root's level bounds the
+		// depth, and a tree of level l holds 2^l - 1 nodes or more.
+		if root != nil {
+			assert.True(t, root.depth() <= 2*root.level(), "key %v", root.key)
+			assert.True(t, float64(root.level()) <= math.Log2(float64(len(nodes)+1)),
+				"level %d of %d nodes", root.level(), len(nodes))
+		}
+	})
+}
@@ -1,13 +1,25 @@
 package tree
 
+import "errors"
+
 var (
+	// AvlFnSet performs AVL rebalancing on inserts
+	// and deletes, and does nothing on lookups.
 	AvlFnSet = &FnSet{
-		InsertFn: avlInsert,
-		DeleteFn: avlDelete,
-		SearchFn: nopNode,
+		InsertFn:  avlInsert,
+		DeleteFn:  avlDelete,
+		SearchFn:  nopNode,
+		PayloadFn: avlPayload,
 	}
 )
 
+// An AVL node's payload is its balance factor,
+// the height of its right subtree minus the height
+// of its left subtree.
+func avlPayload() interface{} {
+	return 0
+}
+
 func (n *node) balance() int {
 	if n == nil {
 		return 0
@@ -15,22 +27,121 @@ func (n *node) balance() int {
 	return n.payload.(int)
 }
 
-func avlRotateRL(a, b *node) *node { return nil }
-func avlRotateLR(a, b *node) *node { return nil }
-func avlRotateR(a, b *node) *node  { return nil }
-func avlRotateL(a, b *node) *node  { return nil }
+// AVLValid returns whether the given BST is a valid AVL tree
+func AVLValid(bst *BST) (bool, error) {
+	b, _, err := bst.root.AVLValid()
+	return b, err
+}
+
+// AVLValid returns whether the given node is a valid AVL Subtree.
+// It returns boolean validity, a potential error
+// and the height of the subtree.
+func (n *node) AVLValid() (bool, int, error) {
+	if n == nil {
+		return true, 0, nil
+	}
+	bal, ok := n.payload.(int)
+	if !ok {
+		return false, 0, errors.New("A node did not have a balance factor")
+	}
+	b, h1, err := n.left.AVLValid()
+	if !b {
+		return b, 0, err
+	}
+	b, h2, err := n.right.AVLValid()
+	if !b {
+		return b, 0, err
+	}
+	if h2-h1 != bal {
+		return false, 0, errors.New("A node's balance factor did not match its subtrees")
+	}
+	if bal < -1 || bal > 1 {
+		return false, 0, errors.New("A node's subtree heights differed by more than one")
+	}
+	if h1 < h2 {
+		h1 = h2
+	}
+	return true, h1 + 1, nil
+}
+
+// avlRotateRL rotates z, the right child of a, right,
+// then rotates a left, returning the new root of the subtree.
+func avlRotateRL(a, z *node) *node {
+	y := z.left
+	z.rightRotate()
+	a.leftRotate()
+	switch y.balance() {
+	case 0:
+		a.payload = 0
+		z.payload = 0
+	case 1:
+		a.payload = -1
+		z.payload = 0
+	default:
+		a.payload = 0
+		z.payload = 1
+	}
+	y.payload = 0
+	return y
+}
+
+// avlRotateLR mirrors avlRotateRL.
+func avlRotateLR(a, z *node) *node {
+	y := z.right
+	z.leftRotate()
+	a.rightRotate()
+	switch y.balance() {
+	case 0:
+		a.payload = 0
+		z.payload = 0
+	case -1:
+		a.payload = 1
+		z.payload = 0
+	default:
+		a.payload = 0
+		z.payload = -1
+	}
+	y.payload = 0
+	return y
+}
+
+// avlRotateR rotates a right around its left child z,
+// returning z. z only has a balance of 0 following
+// a deletion.
+func avlRotateR(a, z *node) *node {
+	a.rightRotate()
+	if z.balance() == 0 {
+		a.payload = -1
+		z.payload = 1
+	} else {
+		a.payload = 0
+		z.payload = 0
+	}
+	return z
+}
+
+// avlRotateL mirrors avlRotateR.
+func avlRotateL(a, z *node) *node {
+	a.leftRotate()
+	if z.balance() == 0 {
+		a.payload = 1
+		z.payload = -1
+	} else {
+		a.payload = 0
+		z.payload = 0
+	}
+	return z
+}
 
-func avlInsert(n *node) *node {
-	var g, p, s *node
+func avlInsert(n *node) (newRoot *node) {
+	var p, s *node
 	for {
 		p = n.parent
 		if p == nil {
-			break
+			return
 		}
-
 		if n == p.right {
 			if p.balance() > 0 {
-				g = p.parent
 				if n.balance() < 0 {
 					s = avlRotateRL(p, n)
 				} else {
@@ -39,7 +150,7 @@ func avlInsert(n *node) *node {
 			} else {
 				if p.balance() < 0 {
 					p.payload = 0
-					break
+					return
 				}
 				p.payload = 1
 				n = p
@@ -47,7 +158,6 @@ func avlInsert(n *node) *node {
 			}
 		} else {
 			if p.balance() < 0 {
-				g = p.parent
 				if n.balance() > 0 {
 					s = avlRotateLR(p, n)
 				} else {
@@ -56,30 +166,109 @@ func avlInsert(n *node) *node {
 			} else {
 				if p.balance() > 0 {
 					p.payload = 0
-					break
+					return
 				}
 				p.payload = -1
 				n = p
 				continue
 			}
 		}
+		// After an insert, a rotation restores
+		// the subtree's original height.
+		if s.parent == nil {
+			newRoot = s
+		}
+		return
+	}
+}
 
-		s.parent = g
-		if g != nil {
-			if p == g.left {
-				g.left = s
-			} else {
-				g.right = s
+func avlDelete(n *node) (newRoot *node) {
+	// p is the lowest node whose subtree has shrunk
+	// on its left side if left is true, and on its
+	// right side otherwise.
+	var p *node
+	var left bool
+	if n.left == nil || n.right == nil {
+		c := n.left
+		if c == nil {
+			c = n.right
+		}
+		p = n.parent
+		if p != nil {
+			left = p.left == n
+		}
+		newRoot = n.parentReplace(c)
+	} else {
+		// Replace n with its successor
+		n2 := n.right.minKey()
+		if n2.parent == n {
+			p = n2
+			left = false
+		} else {
+			p = n2.parent
+			left = true
+			n2.parentReplace(n2.right)
+			n2.right = n.right
+			n2.right.parent = n2
+		}
+		newRoot = n.parentReplace(n2)
+		n2.left = n.left
+		n2.left.parent = n2
+		n2.touch()
+		n2.payload = n.payload
+	}
+	var s *node
+	for p != nil {
+		gp := p.parent
+		gpLeft := gp != nil && gp.left == p
+		if left {
+			switch p.balance() {
+			case 0:
+				p.payload = 1
+				return
+			case -1:
+				p.payload = 0
+			default:
+				z := p.right
+				bal := z.balance()
+				if bal < 0 {
+					s = avlRotateRL(p, z)
+				} else {
+					s = avlRotateL(p, z)
+				}
+				if s.parent == nil {
+					newRoot = s
+				}
+				// The subtree's height did not change
+				if bal == 0 {
+					return
+				}
 			}
-			break
 		} else {
-			return s
+			switch p.balance() {
+			case 0:
+				p.payload = -1
+				return
+			case 1:
+				p.payload = 0
+			default:
+				z := p.left
+				bal := z.balance()
+				if bal > 0 {
+					s = avlRotateLR(p, z)
+				} else {
+					s = avlRotateR(p, z)
+				}
+				if s.parent == nil {
+					newRoot = s
+				}
+				if bal == 0 {
+					return
+				}
+			}
 		}
+		p = gp
+		left = gpLeft
 	}
-	return nil
-
-}
-func avlDelete(n *node) *node {
-	//Todo
-	return nil
+	return
 }
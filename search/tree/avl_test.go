@@ -0,0 +1,169 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validFn func(*BST) (bool, error)
+
+func TestAVLDefinedInput1(t *testing.T) {
+	testDefinedInput(t, AVL, AVLValid)
+}
+
+func TestAVLRandomInput(t *testing.T) {
+	testRandomInput(t, AVL, AVLValid)
+}
+
+func TestAVLSortedInput(t *testing.T) {
+	tree := New(AVL)
+	for _, v := range randomInputNoDupes() {
+		tree.Insert(v)
+	}
+	valid, err := AVLValid(tree.(*BST))
+	assert.True(t, valid)
+	assert.Nil(t, err)
+	_, h, _ := tree.(*BST).root.AVLValid()
+	// An AVL tree of n nodes is at most ~1.44 lg(n) tall
+	assert.True(t, h <= 21)
+}
+
+func testDefinedInput(t *testing.T, typ Type, valid validFn) {
+	tree := New(typ)
+	for _, v := range test1Input {
+		tree.Insert(v)
+		assert.Equal(t, tree.Size(), tree.(*BST).calcSize())
+		ok, err := valid(tree.(*BST))
+		assert.True(t, ok)
+		assert.Nil(t, err)
+	}
+	assert.True(t, tree.(*BST).isValid())
+
+	for _, v := range test1Input {
+		b, found := tree.Search(v.key)
+		assert.True(t, b)
+		assert.Equal(t, found, v.val)
+	}
+	for i := notInInput1; i < notInInput1+10; i++ {
+		b, found := tree.Search(float64(i))
+		assert.False(t, b)
+		assert.Nil(t, found)
+	}
+
+	for _, v := range test1Input {
+		err := tree.Delete(v)
+		assert.Nil(t, err)
+		assert.Equal(t, tree.Size(), tree.(*BST).calcSize())
+		b, found := tree.Search(v.key)
+		assert.False(t, b)
+		assert.Nil(t, found)
+		ok, err := valid(tree.(*BST))
+		assert.True(t, ok)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.True(t, tree.(*BST).isValid())
+	}
+}
+
+func testRandomInput(t *testing.T, typ Type, valid validFn) {
+	tree := New(typ)
+	for i := 0; i < randomInputCt; i++ {
+		n := testNode{
+			compFloat(float64(rand.Intn(randomInputRange))),
+			compFloat(float64(rand.Intn(randomInputRange))),
+		}
+		tree.Insert(n)
+		ok, err := valid(tree.(*BST))
+		assert.True(t, ok)
+		assert.Equal(t, tree.Size(), tree.(*BST).calcSize())
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		// Searching restructures some tree types
+		tree.Search(compFloat(float64(rand.Intn(randomInputRange))))
+	}
+	totalSize := tree.Size()
+	for i := 0; i < randomInputCt; i++ {
+		n := nilValNode{compFloat(float64(rand.Intn(randomInputRange)))}
+		err := tree.Delete(n)
+		if err == nil {
+			totalSize--
+		}
+		ok, err := valid(tree.(*BST))
+		assert.Equal(t, totalSize, tree.Size())
+		assert.Equal(t, tree.Size(), tree.(*BST).calcSize())
+		assert.True(t, ok)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+}
+
+// testSortedInput inserts and then deletes the near-sorted
+// input a sweep line produces, checking validity throughout.
+func testSortedInput(t *testing.T, typ Type, valid validFn) {
+	tree := New(typ)
+	input := nearSortedInput()[:randomInputCt/10]
+	for _, v := range input {
+		tree.Insert(v)
+		ok, err := valid(tree.(*BST))
+		assert.True(t, ok)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+	for _, v := range input {
+		err := tree.Delete(v)
+		assert.Nil(t, err)
+		ok, err := valid(tree.(*BST))
+		assert.True(t, ok)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+	assert.Equal(t, 0, tree.Size())
+}
+
+// invariantInputCt is the number of keys testRandomOps inserts.
+// Each check walks the whole tree, so it is kept small.
+const invariantInputCt = 1000
+
+// testRandomOps inserts the keys below invariantInputCt in a
+// random order, deleting an inserted key at random after about
+// every third insert, and then deletes the rest, calling check
+// on the root of the tree after each insert and delete.
+func testRandomOps(t *testing.T, typ Type, check func(*testing.T, *node)) {
+	rnd := rand.New(rand.NewSource(1))
+	tree := New(typ).(*BST)
+	in := []int{}
+	for _, k := range rnd.Perm(invariantInputCt) {
+		assert.Nil(t, tree.Insert(testNode{compFloat(k), compFloat(k)}))
+		in = append(in, k)
+		check(t, tree.root)
+		if rnd.Intn(3) == 0 {
+			i := rnd.Intn(len(in))
+			assert.Nil(t, tree.Delete(nilValNode{compFloat(in[i])}))
+			in = append(in[:i], in[i+1:]...)
+			check(t, tree.root)
+		}
+		if t.Failed() {
+			t.FailNow()
+		}
+	}
+	for _, i := range rnd.Perm(len(in)) {
+		assert.Nil(t, tree.Delete(nilValNode{compFloat(in[i])}))
+		check(t, tree.root)
+		if t.Failed() {
+			t.FailNow()
+		}
+	}
+	assert.Nil(t, tree.root)
+}
+
+// nodes returns the nodes of n's subtree in order.
+func (n *node) nodes() []*node {
+	return n.flatten(nil)
+}
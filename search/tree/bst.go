@@ -4,7 +4,6 @@ import (
 	"errors"
 
 	"github.com/nylen/go-compgeo/search"
-	"github.com/nylen/go-compgeo/search/tree/fullCopy"
 	"github.com/nylen/go-compgeo/search/tree/static"
 )
 
@@ -33,7 +32,7 @@ func (bst *BST) isValid() bool {
 
 // ToPersistent converts this BST into a Persistent BST.
 func (bst *BST) ToPersistent() search.DynamicPersistent {
-	return fullCopy.NewFullPersistentBST(bst)
+	return NewPersistentBST(bst)
 }
 
 // ToStatic on a BST figures out where all nodes
@@ -67,8 +66,9 @@ func (bst *BST) Insert(inNode search.Node) error {
 	n := new(node)
 	n.key = inNode.Key()
 	n.val = []search.Equalable{inNode.Val()}
-	// We can't do this once we have more than RB trees wow
-	n.payload = red
+	if bst.PayloadFn != nil {
+		n.payload = bst.PayloadFn()
+	}
 	var parent *node
 	curNode := bst.root
 	for {
@@ -84,6 +84,7 @@ func (bst *BST) Insert(inNode search.Node) error {
 		} else if r == search.Equal {
 			// All values of the same key are stored at the same node
 			curNode.val = append(curNode.val, inNode.Val())
+			curNode.touch()
 			bst.size++
 			return nil
 		} else {
@@ -100,10 +101,10 @@ func (bst *BST) Insert(inNode search.Node) error {
 		} else {
 			parent.right = n
 		}
+		parent.touch()
 		// if parent == nil and curNode == nil,
 		// this bst is empty.
 	} else {
-		n.payload = black
 		bst.root = n
 	}
 
@@ -135,6 +136,7 @@ func (bst *BST) Delete(n search.Node) error {
 		for vi := 0; vi < len(curNode.val); vi++ {
 			if v.Equals(curNode.val[vi]) {
 				curNode.val = append(curNode.val[:vi], curNode.val[vi+1:]...)
+				curNode.touch()
 				bst.size--
 				return nil
 			}
@@ -146,13 +148,18 @@ func (bst *BST) Delete(n search.Node) error {
 	return nil
 }
 
-// Search :
+// Search returns whether key is in bst, and its value if so.
+// Only splay trees restructure themselves on Search, so other
+// trees may be searched from any number of goroutines at once.
+// SearchUp and SearchDown never modify bst.
 func (bst *BST) Search(key interface{}) (bool, interface{}) {
 	curNode, isReal := bst.search(key)
 	if !isReal {
 		return false, nil
 	}
-	bst.updateRoot(bst.SearchFn(curNode))
+	if n := bst.SearchFn(curNode); n != nil {
+		bst.updateRoot(n)
+	}
 	return true, curNode.val[0]
 }
 
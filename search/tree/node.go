@@ -18,6 +18,11 @@ type node struct {
 	payload interface{}
 
 	left, right, parent *node
+	// snap is the immutable copy of this node taken the last
+	// time a persistent tree left an instant behind, or nil
+	// if this node or one of its descendants has been modified
+	// since.
+	snap *node
 }
 
 func (n *node) calcSize() int {
@@ -182,9 +187,20 @@ func (n *node) parentReplace(n2 *node) *node {
 	if n2 != nil {
 		n2.parent = n.parent
 	}
+	n.parent.touch()
 	return toReturn
 }
 
+// touch marks n and its ancestors as modified
+// since their last snapshot. Any function which changes
+// a node's children or values needs to touch that node.
+func (n *node) touch() {
+	for n != nil && n.snap != nil {
+		n.snap = nil
+		n = n.parent
+	}
+}
+
 func (n *node) leftRotate() (newRoot *node) {
 	r := n.right
 	n.right = r.left
@@ -203,6 +219,9 @@ func (n *node) leftRotate() (newRoot *node) {
 	}
 	r.left = n
 	n.parent = r
+	n.touch()
+	r.touch()
+	r.parent.touch()
 	return
 }
 
@@ -224,6 +243,9 @@ func (n *node) rightRotate() (newRoot *node) {
 	}
 	l.right = n
 	n.parent = l
+	n.touch()
+	l.touch()
+	l.parent.touch()
 	return
 }
 
@@ -0,0 +1,333 @@
+package tree
+
+import (
+	"errors"
+	"math"
+
+	"github.com/nylen/go-compgeo/geom"
+	"github.com/nylen/go-compgeo/printutil"
+	"github.com/nylen/go-compgeo/search"
+	"github.com/nylen/go-compgeo/search/tree/static"
+)
+
+// PersistentBST is a partially persistent binary search tree.
+// Only the most recent instant can be modified. Whenever a new
+// instant is set, the previous instant is frozen by path copying:
+// only nodes which were modified during that instant, and their
+// ancestors, are copied, and everything else is shared with the
+// instant before it. On a balanced tree this costs O(log n) extra
+// space per update, instead of the O(n) per instant a full copy
+// would.
+type PersistentBST struct {
+	// The BST at the current instant
+	*BST
+	instant float64
+	// Implicitly sorted
+	instants []frozenBST
+}
+
+// NewPersistentBST returns a PersistentBST whose current instant
+// is bst.
+func NewPersistentBST(bst *BST) *PersistentBST {
+	pbst := new(PersistentBST)
+	pbst.BST = bst
+	pbst.instant = math.MaxFloat64 * -1
+	return pbst
+}
+
+// ThisInstant returns the subtree at the most recent
+// instant set
+func (pbst *PersistentBST) ThisInstant() search.Dynamic {
+	return pbst.BST
+}
+
+// AtInstant returns the subtree of pbst at the given instant.
+// Subtrees of instants before the current instant cannot be
+// modified.
+func (pbst *PersistentBST) AtInstant(ins float64) search.Dynamic {
+	if ins > pbst.instant || geom.F64eq(ins, pbst.instant) {
+		return pbst.BST
+	}
+	// binary search for the last instant at or before ins
+	bot := 0
+	top := len(pbst.instants) - 1
+	for bot < top {
+		mid := (bot + top + 1) / 2
+		v := pbst.instants[mid].instant
+		if v < ins || geom.F64eq(v, ins) {
+			bot = mid
+		} else {
+			top = mid - 1
+		}
+	}
+	if bot > top {
+		return pbst.BST
+	}
+	return &pbst.instants[bot]
+}
+
+// ToStaticPersistent returns a static peristent version
+// of the pbst
+func (pbst *PersistentBST) ToStaticPersistent() search.StaticPersistent {
+	// Todo
+	return nil
+}
+
+// MinInstant returns the minimum instant ever set on pbst.
+func (pbst *PersistentBST) MinInstant() float64 {
+	if len(pbst.instants) == 0 {
+		return pbst.instant
+	}
+	return pbst.instants[0].instant
+}
+
+// MaxInstant returns the maximum instant ever set on pbst.
+func (pbst *PersistentBST) MaxInstant() float64 {
+	return pbst.instant
+}
+
+// SetInstant freezes the current instant and increments
+// the pbst to the given instant.
+func (pbst *PersistentBST) SetInstant(ins float64) {
+	if ins < pbst.instant {
+		panic("Decreasing instants is not yet supported")
+	} else if ins == pbst.instant {
+		return
+	}
+	pbst.instants = append(pbst.instants, frozenBST{
+		root:    pbst.root.freeze(),
+		size:    pbst.size,
+		instant: pbst.instant,
+	})
+	pbst.instant = ins
+}
+
+// Copy returns a copy of pbst. Frozen instants are
+// shared between pbst and its copy.
+func (pbst *PersistentBST) Copy() interface{} {
+	cp := new(PersistentBST)
+	cp.BST = pbst.BST.Copy().(*BST)
+	cp.instant = pbst.instant
+	cp.instants = make([]frozenBST, len(pbst.instants))
+	copy(cp.instants, pbst.instants)
+	return cp
+}
+
+// String returns a string representation of pbst.
+func (pbst *PersistentBST) String() string {
+	s := ""
+	for _, ins := range pbst.instants {
+		s += printutil.Stringf64(ins.instant) + ":\n"
+		s += ins.String()
+	}
+	s += printutil.Stringf64(pbst.instant) + ":\n"
+	s += pbst.BST.String()
+	return s
+}
+
+// freeze returns an immutable copy of n's subtree, reusing
+// the snapshots of any nodes unmodified since they were taken.
+// Frozen nodes have no parent pointers, as they can be shared
+// between instants.
+func (n *node) freeze() *node {
+	if n == nil {
+		return nil
+	}
+	if n.snap != nil {
+		return n.snap
+	}
+	cp := new(node)
+	cp.key = n.key
+	cp.val = make([]search.Equalable, len(n.val))
+	copy(cp.val, n.val)
+	cp.left = n.left.freeze()
+	cp.right = n.right.freeze()
+	n.snap = cp
+	return cp
+}
+
+// A frozenBST is a past instant of a PersistentBST.
+// As its nodes have no parent pointers, searches keep track
+// of the path they took from the root.
+type frozenBST struct {
+	root    *node
+	size    int
+	instant float64
+}
+
+var errFrozen = errors.New("Past instants cannot be modified")
+
+func (fb *frozenBST) Insert(search.Node) error {
+	return errFrozen
+}
+
+func (fb *frozenBST) Delete(search.Node) error {
+	return errFrozen
+}
+
+func (fb *frozenBST) Size() int {
+	return fb.size
+}
+
+func (fb *frozenBST) Copy() interface{} {
+	cp := *fb
+	return &cp
+}
+
+func (fb *frozenBST) InOrderTraverse() []search.Node {
+	return inOrderTraverse(fb.root)
+}
+
+func (fb *frozenBST) ToStatic() search.Static {
+	m, maxIndex := fb.root.staticTree(make(map[int]*static.Node), 1)
+	staticBst := make(static.BST, maxIndex+1)
+	for k, v := range m {
+		staticBst[k] = v
+	}
+	return &staticBst
+}
+
+func (fb *frozenBST) String() string {
+	s := fb.root.string("", true)
+	if s == "" {
+		return "<Empty BST>\n"
+	}
+	return s
+}
+
+// search returns the path from the root to the node
+// holding key, or to the last node visited if there is none.
+func (fb *frozenBST) search(key interface{}) ([]*node, bool) {
+	path := []*node{}
+	curNode := fb.root
+	for curNode != nil {
+		path = append(path, curNode)
+		r := curNode.key.Compare(key)
+		if r == search.Equal {
+			return path, true
+		} else if r == search.Greater {
+			curNode = curNode.left
+		} else if r == search.Less {
+			curNode = curNode.right
+		} else {
+			panic("Invalid types for BST operations")
+		}
+	}
+	return path, false
+}
+
+// successor returns the path to the successor of the
+// last node in path, or nil if it has none.
+func successor(path []*node) []*node {
+	n := path[len(path)-1]
+	if n.right != nil {
+		path = append(path, n.right)
+		for n = n.right; n.left != nil; n = n.left {
+			path = append(path, n.left)
+		}
+		return path
+	}
+	for i := len(path) - 2; i >= 0; i-- {
+		if path[i].left == path[i+1] {
+			return path[:i+1]
+		}
+	}
+	return nil
+}
+
+// predecessor mirrors successor.
+func predecessor(path []*node) []*node {
+	n := path[len(path)-1]
+	if n.left != nil {
+		path = append(path, n.left)
+		for n = n.left; n.right != nil; n = n.right {
+			path = append(path, n.right)
+		}
+		return path
+	}
+	for i := len(path) - 2; i >= 0; i-- {
+		if path[i].right == path[i+1] {
+			return path[:i+1]
+		}
+	}
+	return nil
+}
+
+func (fb *frozenBST) Search(key interface{}) (bool, interface{}) {
+	path, ok := fb.search(key)
+	if !ok {
+		return false, nil
+	}
+	return true, path[len(path)-1].val[0]
+}
+
+// SearchUp acts as BST.SearchUp.
+func (fb *frozenBST) SearchUp(key interface{}, up int) (search.Comparable, interface{}) {
+	path, ok := fb.search(key)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	n := path[len(path)-1]
+	if !ok {
+		v := successor(path)
+		if v != nil &&
+			!((v[len(v)-1].key.Compare(n.key) == search.Greater) &&
+				(n.key.Compare(key) == search.Greater)) {
+			path = v
+		}
+	}
+	for i := 0; i < up; i++ {
+		v := successor(path)
+		if v == nil {
+			break
+		}
+		path = v
+	}
+	n = path[len(path)-1]
+	return n.key, n.val[0]
+}
+
+// SearchDown acts as BST.SearchDown.
+func (fb *frozenBST) SearchDown(key interface{}, down int) (search.Comparable, interface{}) {
+	path, ok := fb.search(key)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	n := path[len(path)-1]
+	if !ok {
+		v := predecessor(path)
+		if v != nil &&
+			!((v[len(v)-1].key.Compare(n.key) == search.Less) &&
+				n.key.Compare(key) == search.Less) {
+			path = v
+		}
+	}
+	for i := 0; i < down; i++ {
+		v := predecessor(path)
+		if v == nil {
+			break
+		}
+		path = v
+	}
+	n = path[len(path)-1]
+	return n.key, n.val[0]
+}
+
+// frozenNodes returns how many distinct nodes are stored
+// across all frozen instants.
+func (pbst *PersistentBST) frozenNodes() int {
+	seen := make(map[*node]bool)
+	for _, ins := range pbst.instants {
+		ins.root.countUnseen(seen)
+	}
+	return len(seen)
+}
+
+func (n *node) countUnseen(seen map[*node]bool) {
+	if n == nil || seen[n] {
+		return
+	}
+	seen[n] = true
+	n.left.countUnseen(seen)
+	n.right.countUnseen(seen)
+}
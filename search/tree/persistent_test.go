@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,3 +103,90 @@ func BenchmarkPBSTDefinedInput1(b *testing.B) {
 		}
 	}
 }
+
+func TestPBSTRandomInput(t *testing.T) {
+	for _, typ := range []Type{AVL, RedBlack, Splay} {
+		tr := New(typ).ToPersistent()
+		// Record which keys should exist at each instant
+		expected := make([]map[compFloat]int, 0)
+		present := make(map[compFloat]int)
+		for i := 0; i < randomInputCt/10; i++ {
+			tr.SetInstant(float64(i))
+			n := testNode{
+				compFloat(float64(rand.Intn(randomInputRange / 10))),
+				compFloat(float64(i)),
+			}
+			if rand.Intn(3) == 0 {
+				if tr.Delete(nilValNode{n.key}) == nil {
+					present[n.key]--
+				}
+			} else {
+				tr.Insert(n)
+				present[n.key]++
+			}
+			cp := make(map[compFloat]int, len(present))
+			for k, v := range present {
+				if v > 0 {
+					cp[k] = v
+				}
+			}
+			expected = append(expected, cp)
+		}
+		for i, ex := range expected {
+			t2 := tr.AtInstant(float64(i))
+			size := 0
+			for _, v := range ex {
+				size += v
+			}
+			assert.Equal(t, size, t2.Size())
+			keyRange := compFloat(randomInputRange / 10)
+			var min, max compFloat = keyRange, -1
+			for k := compFloat(0); k < keyRange; k++ {
+				found, _ := t2.Search(k)
+				assert.Equal(t, ex[k] > 0, found)
+				if ex[k] > 0 {
+					if k < min {
+						min = k
+					}
+					max = k
+				}
+			}
+			if size == 0 {
+				continue
+			}
+			// Rounding up and down between keys,
+			// clamped to the extremes of the tree
+			q := compFloat(rand.Intn(randomInputRange/10)) + 0.5
+			expUp := max
+			for k := q + 0.5; k < keyRange; k++ {
+				if ex[k] > 0 {
+					expUp = k
+					break
+				}
+			}
+			expDown := min
+			for k := q - 0.5; k >= 0; k-- {
+				if ex[k] > 0 {
+					expDown = k
+					break
+				}
+			}
+			up, _ := t2.SearchUp(q, 0)
+			down, _ := t2.SearchDown(q, 0)
+			assert.Equal(t, expUp, up)
+			assert.Equal(t, expDown, down)
+		}
+	}
+}
+
+func TestPBSTSpace(t *testing.T) {
+	tr := New(RedBlack).ToPersistent()
+	for i := 0; i < randomInputCt; i++ {
+		tr.SetInstant(float64(i))
+		tr.Insert(testNode{compFloat(float64(i)), compFloat(float64(i))})
+	}
+	// Each insert should copy a path of logarithmic length,
+	// rather than the whole tree.
+	nodes := tr.(*PersistentBST).frozenNodes()
+	assert.True(t, nodes < randomInputCt*40)
+}
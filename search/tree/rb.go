@@ -12,12 +12,18 @@ var (
 	// RB delete for inserts and deletes,
 	// and does nothing on lookups.
 	RbFnSet = &FnSet{
-		InsertFn: rbInsert,
-		DeleteFn: rbDelete,
-		SearchFn: nopNode,
+		InsertFn:  rbInsert,
+		DeleteFn:  rbDelete,
+		SearchFn:  nopNode,
+		PayloadFn: rbPayload,
 	}
 )
 
+// New nodes are red
+func rbPayload() interface{} {
+	return red
+}
+
 // For readability
 func (n *node) isRed() bool {
 	return !n.isBlack()
@@ -179,6 +185,7 @@ func rbDelete(n *node) (newRoot *node) {
 		newRoot = root(newRoot, n.parentReplace(n2))
 		n2.left = n.left
 		n2.left.parent = n2
+		n2.touch()
 		n2.payload = n.payload
 		if p == n {
 			p = n2
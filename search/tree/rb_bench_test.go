@@ -12,7 +12,7 @@ var (
 )
 
 func BenchmarkRBDynamic1(b *testing.B) {
-	benchmarkRBDynamic(b, test1Input, notInInput1)
+	benchmarkDynamic(b, RedBlack, test1Input, notInInput1)
 }
 func BenchmarkRBStatic1(b *testing.B) {
 	benchmarkRBStatic(b, test1Input, notInInput1)
@@ -21,7 +21,7 @@ func BenchmarkMap1(b *testing.B) {
 	benchmarkMap(b, test1Input, notInInput1)
 }
 func BenchmarkRBDynamic2(b *testing.B) {
-	benchmarkRBDynamic(b, test2Input, notInInput2)
+	benchmarkDynamic(b, RedBlack, test2Input, notInInput2)
 }
 func BenchmarkRBStatic2(b *testing.B) {
 	benchmarkRBStatic(b, test2Input, notInInput2)
@@ -31,7 +31,7 @@ func BenchmarkMap2(b *testing.B) {
 }
 func BenchmarkRBDynamic3(b *testing.B) {
 	randomInput := randomInput()
-	benchmarkRBDynamic(b, randomInput, randomInputRange+1)
+	benchmarkDynamic(b, RedBlack, randomInput, randomInputRange+1)
 }
 func BenchmarkRBStatic3(b *testing.B) {
 	randomInput := randomInput()
@@ -43,7 +43,7 @@ func BenchmarkMap3(b *testing.B) {
 }
 func BenchmarkRBDynamic4(b *testing.B) {
 	randomInput := randomInputNoDupes()
-	benchmarkRBDynamic(b, randomInput, randomInputRange+1)
+	benchmarkDynamic(b, RedBlack, randomInput, randomInputRange+1)
 }
 func BenchmarkRBStatic4(b *testing.B) {
 	randomInput := randomInputNoDupes()
@@ -54,6 +54,106 @@ func BenchmarkMap4(b *testing.B) {
 	benchmarkMap(b, randomInput, randomInputRange+1)
 }
 
+func BenchmarkAVLDynamic1(b *testing.B) {
+	benchmarkDynamic(b, AVL, test1Input, notInInput1)
+}
+func BenchmarkSplayDynamic1(b *testing.B) {
+	benchmarkDynamic(b, Splay, test1Input, notInInput1)
+}
+func BenchmarkAVLDynamic2(b *testing.B) {
+	benchmarkDynamic(b, AVL, test2Input, notInInput2)
+}
+func BenchmarkSplayDynamic2(b *testing.B) {
+	benchmarkDynamic(b, Splay, test2Input, notInInput2)
+}
+func BenchmarkAVLDynamic3(b *testing.B) {
+	randomInput := randomInput()
+	benchmarkDynamic(b, AVL, randomInput, randomInputRange+1)
+}
+func BenchmarkSplayDynamic3(b *testing.B) {
+	randomInput := randomInput()
+	benchmarkDynamic(b, Splay, randomInput, randomInputRange+1)
+}
+func BenchmarkAVLDynamic4(b *testing.B) {
+	randomInput := randomInputNoDupes()
+	benchmarkDynamic(b, AVL, randomInput, randomInputRange+1)
+}
+func BenchmarkSplayDynamic4(b *testing.B) {
+	randomInput := randomInputNoDupes()
+	benchmarkDynamic(b, Splay, randomInput, randomInputRange+1)
+}
+
+// The Update benchmarks measure inserting and then
+// deleting every input, where balance strategies differ
+// the most.
+func BenchmarkRBUpdate3(b *testing.B) {
+	benchmarkUpdate(b, RedBlack, randomInput())
+}
+func BenchmarkAVLUpdate3(b *testing.B) {
+	benchmarkUpdate(b, AVL, randomInput())
+}
+func BenchmarkSplayUpdate3(b *testing.B) {
+	benchmarkUpdate(b, Splay, randomInput())
+}
+func BenchmarkRBUpdate4(b *testing.B) {
+	benchmarkUpdate(b, RedBlack, randomInputNoDupes())
+}
+func BenchmarkAVLUpdate4(b *testing.B) {
+	benchmarkUpdate(b, AVL, randomInputNoDupes())
+}
+func BenchmarkSplayUpdate4(b *testing.B) {
+	benchmarkUpdate(b, Splay, randomInputNoDupes())
+}
+
+func BenchmarkTreapDynamic3(b *testing.B) {
+	benchmarkDynamic(b, Treap, randomInput(), randomInputRange+1)
+}
+func BenchmarkScapegoatDynamic3(b *testing.B) {
+	benchmarkDynamic(b, Scapegoat, randomInput(), randomInputRange+1)
+}
+func BenchmarkAADynamic3(b *testing.B) {
+	benchmarkDynamic(b, AA, randomInput(), randomInputRange+1)
+}
+func BenchmarkTreapUpdate3(b *testing.B) {
+	benchmarkUpdate(b, Treap, randomInput())
+}
+func BenchmarkScapegoatUpdate3(b *testing.B) {
+	benchmarkUpdate(b, Scapegoat, randomInput())
+}
+func BenchmarkAAUpdate3(b *testing.B) {
+	benchmarkUpdate(b, AA, randomInput())
+}
+func BenchmarkTreapUpdate4(b *testing.B) {
+	benchmarkUpdate(b, Treap, randomInputNoDupes())
+}
+func BenchmarkScapegoatUpdate4(b *testing.B) {
+	benchmarkUpdate(b, Scapegoat, randomInputNoDupes())
+}
+func BenchmarkAAUpdate4(b *testing.B) {
+	benchmarkUpdate(b, AA, randomInputNoDupes())
+}
+
+// The Sweep benchmarks insert and delete in near-sorted order,
+// as a sweep line does.
+func BenchmarkRBSweep(b *testing.B) {
+	benchmarkUpdate(b, RedBlack, nearSortedInput())
+}
+func BenchmarkAVLSweep(b *testing.B) {
+	benchmarkUpdate(b, AVL, nearSortedInput())
+}
+func BenchmarkSplaySweep(b *testing.B) {
+	benchmarkUpdate(b, Splay, nearSortedInput())
+}
+func BenchmarkTreapSweep(b *testing.B) {
+	benchmarkUpdate(b, Treap, nearSortedInput())
+}
+func BenchmarkScapegoatSweep(b *testing.B) {
+	benchmarkUpdate(b, Scapegoat, nearSortedInput())
+}
+func BenchmarkAASweep(b *testing.B) {
+	benchmarkUpdate(b, AA, nearSortedInput())
+}
+
 func randomInput() []testNode {
 	randomInput := make([]testNode, randomInputCt)
 	for i := range randomInput {
@@ -76,8 +176,21 @@ func randomInputNoDupes() []testNode {
 	return randomInput
 }
 
-func benchmarkRBDynamic(b *testing.B, input []testNode, inputLimit int) {
-	tree := New(RedBlack)
+// nearSortedInput returns sorted, unique input where each
+// element has been displaced a short random distance.
+func nearSortedInput() []testNode {
+	input := randomInputNoDupes()
+	for i := range input {
+		j := i + rand.Intn(10)
+		if j < len(input) {
+			input[i], input[j] = input[j], input[i]
+		}
+	}
+	return input
+}
+
+func benchmarkDynamic(b *testing.B, typ Type, input []testNode, inputLimit int) {
+	tree := New(typ)
 	for _, v := range input {
 		tree.Insert(v)
 	}
@@ -91,6 +204,18 @@ func benchmarkRBDynamic(b *testing.B, input []testNode, inputLimit int) {
 	}
 }
 
+func benchmarkUpdate(b *testing.B, typ Type, input []testNode) {
+	for i := 0; i < b.N; i++ {
+		tree := New(typ)
+		for _, v := range input {
+			tree.Insert(v)
+		}
+		for _, v := range input {
+			tree.Delete(v)
+		}
+	}
+}
+
 func benchmarkRBStatic(b *testing.B, input []testNode, inputLimit int) {
 	tree := New(RedBlack)
 	for _, v := range input {
@@ -0,0 +1,173 @@
+package tree
+
+import "errors"
+
+// scapegoatAlpha is the weight balance scapegoat trees maintain:
+// no child's subtree may hold more than this fraction of its
+// parent's subtree. Higher values rebuild less often but allow
+// deeper trees.
+const scapegoatAlpha = 0.7
+
+var (
+	// ScapegoatFnSet rebuilds unbalanced subtrees on inserts
+	// and deletes, and does nothing on lookups.
+	ScapegoatFnSet = &FnSet{
+		InsertFn:  scapegoatInsert,
+		DeleteFn:  scapegoatDelete,
+		SearchFn:  nopNode,
+		PayloadFn: scapegoatPayload,
+	}
+)
+
+// A scapegoat node's payload is the number of nodes in
+// its subtree. Without it we would need the depth of each
+// insert and the size of the tree, which node functions
+// have no access to.
+func scapegoatPayload() interface{} {
+	return 1
+}
+
+func (n *node) weight() int {
+	if n == nil {
+		return 0
+	}
+	return n.payload.(int)
+}
+
+func (n *node) unbalanced() bool {
+	limit := scapegoatAlpha * float64(n.weight())
+	return float64(n.left.weight()) > limit ||
+		float64(n.right.weight()) > limit
+}
+
+// ScapegoatValid returns whether the given BST is a valid scapegoat tree
+func ScapegoatValid(bst *BST) (bool, error) {
+	return bst.root.ScapegoatValid()
+}
+
+// ScapegoatValid returns whether the given node is a valid scapegoat
+// subtree. As every unbalanced subtree is rebuilt as soon as it
+// is found, every node should be in weight balance.
+func (n *node) ScapegoatValid() (bool, error) {
+	if n == nil {
+		return true, nil
+	}
+	if _, ok := n.payload.(int); !ok {
+		return false, errors.New("A node did not have a weight")
+	}
+	b, err := n.left.ScapegoatValid()
+	if !b {
+		return b, err
+	}
+	b, err = n.right.ScapegoatValid()
+	if !b {
+		return b, err
+	}
+	if n.weight() != n.left.weight()+n.right.weight()+1 {
+		return false, errors.New("A node's weight was not the size of its subtree")
+	}
+	if n.unbalanced() {
+		return false, errors.New("A node's subtree was out of weight balance")
+	}
+	return true, nil
+}
+
+func scapegoatInsert(n *node) *node {
+	return scapegoatRetrace(n.parent)
+}
+
+func scapegoatDelete(n *node) (newRoot *node) {
+	// p is the lowest node whose subtree lost a node
+	var p *node
+	if n.left == nil || n.right == nil {
+		c := n.left
+		if c == nil {
+			c = n.right
+		}
+		p = n.parent
+		newRoot = n.parentReplace(c)
+	} else {
+		// Replace n with its successor
+		n2 := n.right.minKey()
+		if n2.parent == n {
+			p = n2
+		} else {
+			p = n2.parent
+			n2.parentReplace(n2.right)
+			n2.right = n.right
+			n2.right.parent = n2
+		}
+		newRoot = n.parentReplace(n2)
+		n2.left = n.left
+		n2.left.parent = n2
+		n2.touch()
+	}
+	return root(scapegoatRetrace(p), newRoot)
+}
+
+// scapegoatRetrace recalculates the weights of n and its
+// ancestors, then rebuilds the subtree of the highest of them
+// which is out of balance, if any.
+func scapegoatRetrace(n *node) *node {
+	var scapegoat *node
+	for ; n != nil; n = n.parent {
+		n.payload = n.left.weight() + n.right.weight() + 1
+		if n.unbalanced() {
+			scapegoat = n
+		}
+	}
+	if scapegoat == nil {
+		return nil
+	}
+	return scapegoat.rebuild()
+}
+
+// rebuild replaces n's subtree with a perfectly balanced
+// subtree of the same nodes, returning the new root
+// of the tree if n was the root.
+func (n *node) rebuild() *node {
+	nodes := make([]*node, 0, n.weight())
+	nodes = n.flatten(nodes)
+	p := n.parent
+	isLeft := p != nil && p.left == n
+	sub := buildBalanced(nodes)
+	sub.parent = p
+	if p == nil {
+		return sub
+	}
+	if isLeft {
+		p.left = sub
+	} else {
+		p.right = sub
+	}
+	p.touch()
+	return nil
+}
+
+func (n *node) flatten(nodes []*node) []*node {
+	if n == nil {
+		return nodes
+	}
+	nodes = n.left.flatten(nodes)
+	nodes = append(nodes, n)
+	return n.right.flatten(nodes)
+}
+
+func buildBalanced(nodes []*node) *node {
+	if len(nodes) == 0 {
+		return nil
+	}
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.left = buildBalanced(nodes[:mid])
+	n.right = buildBalanced(nodes[mid+1:])
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+	n.payload = len(nodes)
+	n.touch()
+	return n
+}
@@ -0,0 +1,78 @@
+package tree
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScapegoatDefinedInput1(t *testing.T) {
+	testDefinedInput(t, Scapegoat, ScapegoatValid)
+}
+
+func TestScapegoatRandomInput(t *testing.T) {
+	testRandomInput(t, Scapegoat, ScapegoatValid)
+}
+
+func TestScapegoatSortedInput(t *testing.T) {
+	testSortedInput(t, Scapegoat, ScapegoatValid)
+}
+
+// depth returns the number of nodes on the
+// longest path down from n.
+func (n *node) depth() int {
+	if n == nil {
+		return 0
+	}
+	l, r := n.left.depth(), n.right.depth()
+	if l > r {
+		return l + 1
+	}
+	return r + 1
+}
+
+func TestScapegoatWeightBalance(t *testing.T) {
+	testRandomOps(t, Scapegoat, func(t *testing.T, root *node) {
+		nodes := root.nodes()
+		for _, n := range nodes {
+			w, ok := n.payload.(int)
+			if !assert.True(t, ok, "key %v has no weight", n.key) {
+				return
+			}
+			size := len(n.left.nodes()) + len(n.right.nodes()) + 1
+			assert.Equal(t, size, w, "weight of key %v", n.key)
+			for _, c := range []*node{n.left, n.right} {
+				assert.True(t, float64(len(c.nodes())) <= scapegoatAlpha*float64(size),
+					"a child of key %v holds more than alpha of its subtree", n.key)
+			}
+		}
+		// Weight balance at every node bounds the depth
+		// by the log base 1/alpha of the size.
+		if len(nodes) != 0 {
+			bound := math.Log(float64(len(nodes)))/math.Log(1/scapegoatAlpha) + 1
+			assert.True(t, float64(root.depth()) <= bound,
+				"depth %d of %d nodes", root.depth(), len(nodes))
+		}
+	})
+}
+
+func TestScapegoatRebuild(t *testing.T) {
+	// Inserting 1, 2 and 3 in order leaves a chain within
+	// weight balance, as each right child holds no more than
+	// 0.7 of its parent's subtree. Inserting 4 puts the root
+	// out of balance, with 3 of its 4 nodes on the right, so
+	// the whole tree is rebuilt around its middle key.
+	tree := New(Scapegoat).(*BST)
+	for k := 1; k <= 3; k++ {
+		tree.Insert(testNode{compFloat(k), compFloat(k)})
+	}
+	assert.Equal(t, compFloat(1), tree.root.key)
+	assert.Equal(t, 3, tree.root.depth())
+	tree.Insert(testNode{compFloat(4), compFloat(4)})
+	assert.Equal(t, compFloat(3), tree.root.key)
+	assert.Equal(t, 3, tree.root.depth())
+	for _, n := range tree.root.nodes() {
+		assert.False(t, n.unbalanced(), "key %v", n.key)
+	}
+}
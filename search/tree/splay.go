@@ -1,6 +1,12 @@
 package tree
 
+import "errors"
+
 var (
+	// SplayFnSet splays the affected node to the root
+	// on inserts, deletes, and lookups. As lookups with
+	// Search modify the tree, splay trees may not be
+	// searched from more than one goroutine at once.
 	SplayFnSet = &FnSet{
 		InsertFn: splay,
 		DeleteFn: splayDelete,
@@ -39,17 +45,60 @@ func splay(n *node) *node {
 	return n
 }
 
+// splayDelete splays n to the root, then joins
+// its subtrees by splaying the maximum of the left
+// subtree and hanging the right subtree off of it.
 func splayDelete(n *node) *node {
+	splay(n)
+	l, r := n.left, n.right
+	n.left = nil
+	n.right = nil
+	if r != nil {
+		r.parent = nil
+	}
+	if l == nil {
+		return r
+	}
+	l.parent = nil
+	m := splay(l.maxKey())
+	m.right = r
+	if r != nil {
+		r.parent = m
+	}
+	m.touch()
+	return m
+}
 
-	// splay(n)
-	// if n.left != nil {
-	// 	n.right.parent = nil
-	// 	return n.right
-	// } else if n.right != nil {
-	// 	n.left.parent = nil
-	// 	return n.left
-	// } else {
+// SplayValid returns whether the given BST is a valid splay tree.
+// Splay trees carry no balance information, so this only
+// checks that keys are ordered and that parent pointers agree
+// with child pointers.
+func SplayValid(bst *BST) (bool, error) {
+	if bst.root == nil {
+		return true, nil
+	}
+	if bst.root.parent != nil {
+		return false, errors.New("The root has a parent")
+	}
+	if !bst.isValid() {
+		return false, errors.New("Keys were out of order")
+	}
+	return bst.root.parentsValid()
+}
 
-	// }
-	return nil
+func (n *node) parentsValid() (bool, error) {
+	if n == nil {
+		return true, nil
+	}
+	if n.left != nil && n.left.parent != n {
+		return false, errors.New("A left child did not point to its parent")
+	}
+	if n.right != nil && n.right.parent != n {
+		return false, errors.New("A right child did not point to its parent")
+	}
+	b, err := n.left.parentsValid()
+	if !b {
+		return b, err
+	}
+	return n.right.parentsValid()
 }
@@ -0,0 +1,43 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplayDefinedInput1(t *testing.T) {
+	testDefinedInput(t, Splay, SplayValid)
+}
+
+func TestSplayRandomInput(t *testing.T) {
+	testRandomInput(t, Splay, SplayValid)
+}
+
+func TestSplaySearch(t *testing.T) {
+	tree := New(Splay)
+	for _, v := range test2Input {
+		tree.Insert(v)
+	}
+	for _, v := range test2Input {
+		tree.Search(v.key)
+		// The last node searched for is the root
+		assert.Equal(t, v.key, tree.(*BST).root.key)
+	}
+}
+
+func TestSplaySearchUpDown(t *testing.T) {
+	// Only Search splays, so slab decompositions over splay
+	// trees, which only search up and down, may be queried
+	// concurrently.
+	tree := New(Splay)
+	for _, v := range test2Input {
+		tree.Insert(v)
+	}
+	before := tree.(*BST).String()
+	for _, v := range test2Input {
+		tree.SearchUp(v.key, 0)
+		tree.SearchDown(v.key, 1)
+	}
+	assert.Equal(t, before, tree.(*BST).String())
+}
@@ -0,0 +1,83 @@
+package tree
+
+import (
+	"errors"
+	"math/rand"
+)
+
+var (
+	// TreapFnSet rotates nodes to keep their random
+	// priorities in heap order on inserts and deletes,
+	// and does nothing on lookups.
+	TreapFnSet = &FnSet{
+		InsertFn:  treapInsert,
+		DeleteFn:  treapDelete,
+		SearchFn:  nopNode,
+		PayloadFn: treapPayload,
+	}
+)
+
+// A treap node's payload is its priority. Every node's
+// priority is at least that of its children.
+func treapPayload() interface{} {
+	return rand.Int63()
+}
+
+func (n *node) priority() int64 {
+	if n == nil {
+		return -1
+	}
+	return n.payload.(int64)
+}
+
+// TreapValid returns whether the given BST is a valid treap
+func TreapValid(bst *BST) (bool, error) {
+	return bst.root.TreapValid()
+}
+
+// TreapValid returns whether the given node is a valid treap subtree.
+func (n *node) TreapValid() (bool, error) {
+	if n == nil {
+		return true, nil
+	}
+	if _, ok := n.payload.(int64); !ok {
+		return false, errors.New("A node did not have a priority")
+	}
+	if n.left.priority() > n.priority() ||
+		n.right.priority() > n.priority() {
+		return false, errors.New("A node's priority was less than its child's")
+	}
+	b, err := n.left.TreapValid()
+	if !b {
+		return b, err
+	}
+	return n.right.TreapValid()
+}
+
+func treapInsert(n *node) (newRoot *node) {
+	for n.parent != nil && n.parent.priority() < n.priority() {
+		if n.parent.left == n {
+			newRoot = root(n.parent.rightRotate(), newRoot)
+		} else {
+			newRoot = root(n.parent.leftRotate(), newRoot)
+		}
+	}
+	return
+}
+
+func treapDelete(n *node) (newRoot *node) {
+	// Rotate n down until it has at most one child,
+	// promoting whichever child has the higher priority.
+	for n.left != nil && n.right != nil {
+		if n.left.priority() > n.right.priority() {
+			newRoot = root(n.rightRotate(), newRoot)
+		} else {
+			newRoot = root(n.leftRotate(), newRoot)
+		}
+	}
+	c := n.left
+	if c == nil {
+		c = n.right
+	}
+	return root(n.parentReplace(c), newRoot)
+}
@@ -0,0 +1,44 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/nylen/go-compgeo/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreapDefinedInput1(t *testing.T) {
+	testDefinedInput(t, Treap, TreapValid)
+}
+
+func TestTreapRandomInput(t *testing.T) {
+	testRandomInput(t, Treap, TreapValid)
+}
+
+func TestTreapSortedInput(t *testing.T) {
+	testSortedInput(t, Treap, TreapValid)
+}
+
+func TestTreapHeapOrder(t *testing.T) {
+	// Priorities are drawn once, when a node is inserted, and
+	// must stay with their keys through rotations.
+	priorities := map[search.Comparable]int64{}
+	testRandomOps(t, Treap, func(t *testing.T, root *node) {
+		for _, n := range root.nodes() {
+			p, ok := n.payload.(int64)
+			if !assert.True(t, ok, "key %v has no priority", n.key) {
+				return
+			}
+			if old, ok := priorities[n.key]; ok {
+				assert.Equal(t, old, p, "priority of key %v changed", n.key)
+			}
+			priorities[n.key] = p
+			for _, c := range []*node{n.left, n.right} {
+				if c != nil {
+					assert.True(t, c.payload.(int64) <= p,
+						"key %v has a higher priority than its parent %v", c.key, n.key)
+				}
+			}
+		}
+	})
+}
@@ -17,11 +17,11 @@ const (
 	AVL      Type = iota
 	RedBlack      // RB would probably be okay.
 	Splay
+	Treap
+	Scapegoat
+	AA
 	// Consider:
-	// Treap?
-	// Scapegoat tree?
-	// TTree? <- more work than the other two
-	// AA?
+	// TTree?
 )
 
 // FnSet represents the fields that need to
@@ -31,6 +31,10 @@ type FnSet struct {
 	InsertFn func(*node) *node
 	DeleteFn func(*node) *node
 	SearchFn func(*node) *node
+	// PayloadFn, if not nil, returns the payload
+	// a new node is initialized with before InsertFn
+	// is called on it.
+	PayloadFn func() interface{}
 }
 
 // New returns a tree as defined by the input type.
@@ -40,9 +44,15 @@ func New(typ Type) search.Persistable {
 	bst := new(BST)
 	switch typ {
 	case AVL:
-		fallthrough
+		bst.FnSet = AvlFnSet
 	case Splay:
-		fallthrough
+		bst.FnSet = SplayFnSet
+	case Treap:
+		bst.FnSet = TreapFnSet
+	case Scapegoat:
+		bst.FnSet = ScapegoatFnSet
+	case AA:
+		bst.FnSet = AaFnSet
 	default:
 		fallthrough
 	case RedBlack: